@@ -91,6 +91,7 @@ func main() {
 	pluginsFlag := flag.String("plugins", "", "Provide plugins folder")
 	logLevel := flag.String("log-level", "info", "Level for logger")
 	inputFlag := flag.String("input", "", "Input for model")
+	streamFlag := flag.Bool("stream", false, "Print array items as NDJSON as soon as they are built")
 	flag.Parse()
 
 	if *filePath == "" && *urlPath == "" {
@@ -116,6 +117,11 @@ func main() {
 	defer stop()
 
 	cfg := getConfig(*filePath, *urlPath)
+	if *streamFlag {
+		streamNDJSON(ctx, cfg, builder.PureString(*inputFlag), log)
+		return
+	}
+
 	res, err := lib.ParseCtx(ctx, cfg.Item, cfg.Limits, cfg.References, builder.PureString(*inputFlag), log)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
@@ -141,3 +147,21 @@ func main() {
 		_ = clipboard.WriteAll(result)
 	}
 }
+
+// streamNDJSON prints every streamed item as one compact JSON line
+func streamNDJSON(ctx context.Context, cfg *config.CliItem, input builder.Interfacable, log logger.Logger) {
+	for item, err := range lib.StreamCtx(ctx, cfg.Item, cfg.Limits, cfg.References, input, log) {
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			return
+		}
+
+		line := item.ToJson()
+		var compact bytes.Buffer
+		if errCompact := json.Compact(&compact, []byte(line)); errCompact == nil {
+			line = compact.String()
+		}
+
+		fmt.Fprintln(os.Stdout, line)
+	}
+}
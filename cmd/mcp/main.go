@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -28,6 +29,9 @@ import (
 
 const referenceURI = "fitter://config-reference"
 
+// errExecDisabled keeps remote prompts from running commands on the host
+var errExecDisabled = errors.New(`"exec_config" is disabled on this server, start it with --allow-exec (env FITTER_MCP_ALLOW_EXEC=true) to run commands`)
+
 // overridden at release time via -ldflags "-X main.version=..."
 var version = "dev"
 
@@ -63,8 +67,68 @@ func parseCliItem(content []byte) (*config.CliItem, error) {
 	return cfg, nil
 }
 
-func runConfig(ctx context.Context, cfg *config.CliItem, input string) (result string, err error) {
+// checkConfig validates the config and rejects exec connectors anywhere in it
+// (item, generated models, references) unless the server allows them
+func checkConfig(cfg *config.CliItem, allowExec bool) error {
 	if err := agent.ValidateConfig(cfg); err != nil {
+		return err
+	}
+	if !allowExec && usesExec(cfg) {
+		return errExecDisabled
+	}
+	return nil
+}
+
+func usesExec(cfg *config.CliItem) bool {
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		// fail closed
+		return true
+	}
+	var value interface{}
+	if err = json.Unmarshal(raw, &value); err != nil {
+		return true
+	}
+	return hasKey(value, "exec_config")
+}
+
+// hasKey looks for the non-null key at any depth of the json value
+func hasKey(value interface{}, key string) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if (k == key && child != nil) || hasKey(child, key) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if hasKey(child, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// configReferenceText hides exec_config from the clients of the server without it
+func configReferenceText(allowExec bool) string {
+	if allowExec {
+		return configReference
+	}
+
+	lines := strings.Split(configReference, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if !strings.Contains(line, `"exec_config"`) {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+func runConfig(ctx context.Context, cfg *config.CliItem, input string, allowExec bool) (result string, err error) {
+	if err := checkConfig(cfg, allowExec); err != nil {
 		return "", err
 	}
 	defer func() {
@@ -83,14 +147,14 @@ func runConfig(ctx context.Context, cfg *config.CliItem, input string) (result s
 // propagates ctx into the connectors, so cancellation aborts in-flight
 // fetches; the select is a backstop for the parsing work between fetches,
 // which is not context-aware.
-func runConfigCtx(ctx context.Context, cfg *config.CliItem, input string) (string, error) {
+func runConfigCtx(ctx context.Context, cfg *config.CliItem, input string, allowExec bool) (string, error) {
 	type parseOut struct {
 		result string
 		err    error
 	}
 	ch := make(chan parseOut, 1)
 	go func() {
-		result, err := runConfig(ctx, cfg, input)
+		result, err := runConfig(ctx, cfg, input, allowExec)
 		ch <- parseOut{result: result, err: err}
 	}()
 	select {
@@ -107,7 +171,9 @@ func textResult(text string) *mcp.CallToolResult {
 	}
 }
 
-func newServer() *mcp.Server {
+func newServer(allowExec bool) *mcp.Server {
+	reference := configReferenceText(allowExec)
+
 	server := mcp.NewServer(&mcp.Implementation{
 		Name:    "fitter",
 		Title:   "Fitter",
@@ -125,7 +191,7 @@ func newServer() *mcp.Server {
 		if err != nil {
 			return nil, nil, err
 		}
-		res, err := runConfigCtx(ctx, cfg, in.Input)
+		res, err := runConfigCtx(ctx, cfg, in.Input, allowExec)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -145,7 +211,7 @@ func newServer() *mcp.Server {
 		if err != nil {
 			return nil, nil, err
 		}
-		res, err := runConfigCtx(ctx, cfg, in.Input)
+		res, err := runConfigCtx(ctx, cfg, in.Input, allowExec)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -173,7 +239,7 @@ func newServer() *mcp.Server {
 		if err != nil {
 			return nil, nil, err
 		}
-		res, err := runConfigCtx(ctx, cfg, in.Input)
+		res, err := runConfigCtx(ctx, cfg, in.Input, allowExec)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -191,7 +257,7 @@ func newServer() *mcp.Server {
 		if err != nil {
 			return nil, nil, err
 		}
-		if err := agent.ValidateConfig(cfg); err != nil {
+		if err := checkConfig(cfg, allowExec); err != nil {
 			return nil, nil, err
 		}
 		return textResult("valid"), nil, nil
@@ -202,7 +268,7 @@ func newServer() *mcp.Server {
 		Description: "Return a condensed reference of the Fitter config format (connectors, parsers, model/field schema, placeholders, " +
 			"notifiers, references, limits) with working examples. Use it before authoring a config for fitter_run.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, in struct{}) (*mcp.CallToolResult, any, error) {
-		return textResult(configReference), nil, nil
+		return textResult(reference), nil, nil
 	})
 
 	server.AddResource(&mcp.Resource{
@@ -216,7 +282,7 @@ func newServer() *mcp.Server {
 			Contents: []*mcp.ResourceContents{{
 				URI:      referenceURI,
 				MIMEType: "text/markdown",
-				Text:     configReference,
+				Text:     reference,
 			}},
 		}, nil
 	})
@@ -291,6 +357,7 @@ func runHTTP(server *mcp.Server, addr string, authToken string, stateless bool)
 func main() {
 	httpAddr := flag.String("http", os.Getenv("FITTER_MCP_HTTP_ADDR"), "serve MCP over streamable HTTP on this address (e.g. :8080) instead of stdio; env FITTER_MCP_HTTP_ADDR")
 	stateless := flag.Bool("stateless", os.Getenv("FITTER_MCP_STATELESS") == "true", "run the HTTP transport without per-session state, allows load-balancing without sticky sessions; env FITTER_MCP_STATELESS=true")
+	allowExec := flag.Bool("allow-exec", os.Getenv("FITTER_MCP_ALLOW_EXEC") == "true", "allow configs with exec_config to run commands on this host, off by default; env FITTER_MCP_ALLOW_EXEC=true")
 	flag.Parse()
 
 	var realStdout *os.File
@@ -309,7 +376,7 @@ func main() {
 		}
 	}
 
-	server := newServer()
+	server := newServer(*allowExec)
 
 	if *httpAddr != "" {
 		runHTTP(server, *httpAddr, os.Getenv("FITTER_MCP_AUTH_TOKEN"), *stateless)
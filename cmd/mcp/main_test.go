@@ -42,7 +42,7 @@ func textContent(t *testing.T, res *mcp.CallToolResult) string {
 }
 
 func TestHTTPTransport(t *testing.T) {
-	ts := httptest.NewServer(newHTTPHandler(newServer(), "", false))
+	ts := httptest.NewServer(newHTTPHandler(newServer(false), "", false))
 	// registered before connect() registers session cleanup: LIFO order
 	// closes the session first, otherwise Close waits on the open SSE stream
 	t.Cleanup(ts.Close)
@@ -78,7 +78,7 @@ func TestHTTPTransport(t *testing.T) {
 }
 
 func TestHTTPTransportStateless(t *testing.T) {
-	ts := httptest.NewServer(newHTTPHandler(newServer(), "", true))
+	ts := httptest.NewServer(newHTTPHandler(newServer(false), "", true))
 	t.Cleanup(ts.Close)
 
 	session := connect(t, ts.URL)
@@ -91,7 +91,7 @@ func TestHTTPTransportStateless(t *testing.T) {
 }
 
 func TestBearerAuth(t *testing.T) {
-	ts := httptest.NewServer(newHTTPHandler(newServer(), "secret-token", false))
+	ts := httptest.NewServer(newHTTPHandler(newServer(false), "secret-token", false))
 	t.Cleanup(ts.Close)
 
 	resp, err := http.Post(ts.URL+"/mcp", "application/json", strings.NewReader("{}"))
@@ -139,3 +139,63 @@ func (a authRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	req.Header.Set("Authorization", "Bearer "+a.token)
 	return http.DefaultTransport.RoundTrip(req)
 }
+
+const execConfig = `{
+  "item": {
+    "connector_config": {
+      "response_type": "json",
+      "static_config": {"value": "[1]"}
+    },
+    "model": {
+      "array_config": {
+        "item_config": {
+          "field": {
+            "type": "string",
+            "generated": {"model": {
+              "type": "string",
+              "connector_config": {"response_type": "json", "exec_config": {"command": "echo", "args": ["hi"]}},
+              "model": {"base_field": {"type": "string"}}
+            }}
+          }
+        }
+      }
+    }
+  }
+}`
+
+func TestExecDisabledByDefault(t *testing.T) {
+	ts := httptest.NewServer(newHTTPHandler(newServer(false), "", false))
+	t.Cleanup(ts.Close)
+
+	session := connect(t, ts.URL)
+	for _, tool := range []string{"fitter_run", "fitter_validate_config"} {
+		res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+			Name:      tool,
+			Arguments: map[string]any{"config": execConfig},
+		})
+		require.NoError(t, err)
+		assert.True(t, res.IsError)
+		assert.Contains(t, textContent(t, res), "--allow-exec")
+	}
+
+	reference, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: referenceURI})
+	require.NoError(t, err)
+	assert.NotContains(t, reference.Contents[0].Text, "exec_config")
+}
+
+func TestExecAllowed(t *testing.T) {
+	ts := httptest.NewServer(newHTTPHandler(newServer(true), "", false))
+	t.Cleanup(ts.Close)
+
+	session := connect(t, ts.URL)
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "fitter_validate_config",
+		Arguments: map[string]any{"config": execConfig},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "valid", textContent(t, res))
+
+	reference, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: referenceURI})
+	require.NoError(t, err)
+	assert.Contains(t, reference.Contents[0].Text, "exec_config")
+}
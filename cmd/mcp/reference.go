@@ -13,15 +13,25 @@ A config for fitter_run/fitter_run_file is a "CliItem" object (JSON or YAML):
 ## item.connector_config — where the data comes from
 
 {
-  "response_type": "json" | "HTML" | "XML" | "xpath",  // required: how the fetched body is parsed
+  "response_type": "json" | "HTML" | "XML" | "xpath" | "pdf" | "metadata" | "feed",  // required: how the fetched body is parsed
   "url": "https://example.com",                        // used by server/browser connectors; supports placeholders
   "attempts": 3,                                       // optional retries
   "null_on_error": false,                              // return null instead of failing
+  "max_body_bytes": 0, "truncate_body": false,         // optional, overrides limits.max_body_bytes for this connector; truncate_body cuts the body instead of failing
+  "readability": false,                                // HTML only: parse the main content of the article page instead of the page, see below
+  "charset": "",                                       // optional, e.g. "windows-1251"/"shift_jis"/"gbk"; HTML/xpath/XML bodies are transcoded to UTF-8, detected from BOM, Content-Type, xml declaration or <meta charset> when empty
+  "archive": { "format": "auto"|"gzip"|"zstd"|"zip"|"tar"|"tar.gz", "member": "*.csv", "max_size": 104857600 },   // optional, works with any connector: decompress gzip/zstd or extract the first matching zip/tar member (glob on file name, or full path when it contains "/"); fails above max_size BYTES (default 100MB)
 
   // exactly ONE of the following connector configs:
-  "server_config":  { "method": "GET", "headers": {"Authorization": "Bearer {{{RefName=Token}}}"}, "timeout": 30, "body": "", "json_raw_body": {}, "proxy": {"server": "http://host:3128", "username": "", "password": ""}, "oauth2": {"token_url": "https://.../token", "grant_type": "client_credentials"|"refresh_token", "client_id": "{{{FromEnv=ID}}}", "client_secret": "", "scopes": [], "refresh_token": "", "endpoint_params": {}, "auth_style": ""|"header"|"params", "token_file": "~/.fitter/tokens/name.json"} },   // oauth2: token fetched/refreshed/cached automatically, sent as Authorization header; token_file persists rotated refresh tokens between runs (create it once with the "fitter_cli auth" command)
+  "server_config":  { "method": "GET", "headers": {"Authorization": "Bearer {{{RefName=Token}}}"}, "timeout": 30, "body": "", "json_raw_body": {}, "form": {"q": "{PL}"}, "multipart": [{"name": "", "value": "", "file": "", "file_name": "", "content_type": ""}], "proxy": {"server": "http://host:3128", "username": "", "password": ""}, "oauth2": {"token_url": "https://.../token", "grant_type": "client_credentials"|"refresh_token", "client_id": "{{{FromEnv=ID}}}", "client_secret": "", "scopes": [], "refresh_token": "", "endpoint_params": {}, "auth_style": ""|"header"|"params", "token_file": "~/.fitter/tokens/name.json"}, "transport": {"max_idle_conns": 100, "max_idle_conns_per_host": 2, "disable_http2": false, "ca_file": "", "cert_file": "", "key_file": "", "insecure_skip_verify": false, "redirect": "follow"|"none"|"same_host", "max_redirects": 10}, "cookie_jar": {"name": "shop", "file": "~/.fitter/sessions/shop.json", "import_storage_state": ""} },   // form/multipart: structured bodies with automatic Content-Type (multipart parts with "file" upload the file); cookie_jar: cookies kept between requests, shared by connectors with the same name, persisted to file (playwright storage state format), import_storage_state loads a "fitter_cli browser-login" session; oauth2: token fetched/refreshed/cached automatically, sent as Authorization header; token_file persists rotated refresh tokens between runs (create it once with the "fitter_cli auth" command)
+  "graphql_config": { "query": "query($q: String!, $after: String) {...}", "operation_name": "", "variables": {"q": {"type": "string", "value": "{PL}"}}, "server": {"headers": {}, "oauth2": {...}, ...}, "pagination": {"page_info_path": "search.pageInfo", "nodes_path": "search.nodes", "cursor_variable": "after", "max_pages": 0} },   // POST to url; nodes_path "search.edges.#.node" for connections with edges only; result is the response "data" (or the nodes of all pages with pagination); graphql "errors" fail the connector; query is sent as is, use variables for placeholders
+  "sse_config":     { "method": "GET", "headers": {}, "body": "", "event": "", "max_messages": 10, "duration": 60, "until": "fRes.status == 'done'" },   // listen to Server-Sent Events at url; result is a json array of the collected messages; stops at max_messages, until match (included) or after duration SECONDS (default 60)
+  "websocket_config": { "headers": {}, "origin": "", "subscribe": "", "subscribe_raw": {"op": "subscribe"}, "max_messages": 10, "duration": 60, "until": "" },   // same collection as sse_config for ws:// or wss:// url; subscribe(_raw) is sent right after connecting
+  "sql_config":     { "driver": "sqlite"|"postgres"|"mysql", "dsn": "{{{FromEnv=DATABASE_URL}}}", "query": "SELECT * FROM t WHERE id = ?", "args": [{"type": "int", "value": "{PL}"}] },   // rows as json array of objects (use response_type json); args are bind parameters (? for sqlite/mysql, $1 for postgres), never interpolated into the query
+  "exec_config":    { "command": "kubectl", "args": ["get", "pods", "-n", "{PL}", "-o", "json"], "env": {}, "dir": "", "stdin": "", "timeout": 60 },   // runs the command WITHOUT shell, stdout is the body; non-zero exit = error with stderr; process tree killed on timeout (SECONDS)
+  "sitemap_config": { "filter": "/product/[0-9]+$", "lastmod_after": "2024-05-01", "max_depth": 5, "max_urls": 0, "server": { "headers": {} } },   // url is sitemap.xml, sitemap index, .xml.gz, text sitemap or robots.txt; nested indexes followed up to max_depth levels below the url; returns [{"loc", "lastmod", "changefreq", "priority"}] (use response_type json)
   "static_config":  { "value": "string value (can be html/json)", "raw": {"any": "json"} },
-  "file_config":    { "path": "/path/to/file", "use_formatting": false },
+  "file_config":    { "path": "/path/to/file", "use_formatting": false, "glob": "", "recursive": false },   // with glob ("*.html") path is a directory: every matched file is parsed with the model, result is an array of {file_name, path, mod_time, result}
   "int_sequence_config": { "start": 0, "end": 10, "step": 1 },   // [start, end) like range(); good for pagination
   "reference_config": { "name": "MyRef" },             // read prefetched value from top-level references
   "plugin_connector_config": { "name": "my_plugin", "config": {...} },  // requires FITTER_PLUGINS env on the MCP server
@@ -37,6 +47,10 @@ response_type selects the selector language used in field "path" values:
 - "HTML"  -> goquery/CSS selectors, e.g. "div.article > a"
 - "xpath" -> XPath, e.g. "//channel/title/text()" (also usable for HTML pages)
 - "XML"   -> xmlquery/XPath
+- "pdf"   -> gjson paths on {"text", "pages": [...], "total_pages"}
+- "metadata" -> gjson paths on the structured metadata of an HTML page: {"title", "json_ld": [...], "microdata": [{"type", "id", "properties"}], "opengraph": {"title", "image"}, "opengraph_all": {"image": [...]}, "twitter": {...}, "meta": {"description"}, "links": [{"rel", "href"}]}, e.g. "json_ld.#(@type==Product).name", "opengraph.image"
+- "HTML" with "readability": true -> gjson paths on the main content of an HTML article: {"title", "byline", "excerpt", "site_name", "published_time", "lang", "lead_image", "content" (html), "text", "markdown", "length"}
+- "feed"  -> gjson paths on RSS/RDF/Atom/JSON Feed normalized to {"type", "title", "link", "description", "language", "updated", "entries": [{"id", "title", "link", "published", "updated", "author", "summary", "content", "categories", "enclosures": [{"url", "type", "length"}]}]}, dates are RFC 3339 UTC
 
 ## item.model — what to extract
 
@@ -59,9 +73,14 @@ Field:
 
 BaseField:
 {
-  "type": "string"|"int"|"int64"|"float"|"float64"|"boolean"|"html"|"raw_string"|"null"|"array"|"object",
+  "type": "string"|"int"|"int64"|"float"|"float64"|"boolean"|"html"|"raw_string"|"null"|"array"|"object"|"money"|"url"|"markdown",   // money = {"amount": 12.99, "currency": "USD"}; url = absolute normalized link; markdown = selected HTML node (html/xpath) or HTML text (json/xml/feed) as Markdown
   "path": "<selector in the response_type language; relative when inside an array item>",
   "html_attribute": "href",                      // HTML parsing only: take attribute instead of text
+  "transforms": [{ "type": "trim" }, { "type": "replace", "pattern": ",", "replacement": "" }],   // optional ordered cleanup before regex/type conversion: trim, lower, upper, collapse_whitespace, strip_tags, replace(pattern, replacement), regex_replace(pattern, replacement), split(separator) -> array, join(separator), html_unescape, url_decode, base64_decode, substring(start, end; negative from end), default(value; also when not found)
+  "number": { "locale": "de", "magnitude": false, "currency": "EUR" },   // optional for numeric/money types: take the first number of the text ignoring currency/percent/words; locale = decimal separator (guessed when empty); magnitude = K/M/B suffixes; currency = default ISO code for money
+  "url": { "base": "https://example.com/", "strip_tracking": false, "strip_params": ["session"] },   // optional for url type: links resolve against the final connector url and <base href>; base = page url for static/file connectors; strip_tracking = drop utm_*/fbclid/gclid...
+  "rounding": ""|"round"|"floor"|"ceil"|"truncate",   // int/int64 are exact int64; non-integral values are null unless rounded
+  "regex": { "pattern": "Price: (\\d+)", "group": "1", "all": false, "replace": "" },   // optional, applied to the extracted text before type conversion; group by index or name; all = array of every match; replace = template like "$1.$2"; no match = null
   "condition": "fRes > 0",                       // optional expr-lang check on the EXTRACTED value; false = field omitted (no null), generated work skipped
   "generated": <GeneratedFieldConfig>,           // computed instead of extracted
   "first_of": [<BaseField>, ...]
@@ -77,7 +96,8 @@ ArrayConfig:
   "reverse": false,
   "condition": "",                               // optional: false = whole array omitted from the parent
   "item_condition": "fSrc.in_stock && fRes.price > 0",  // optional filter over every BUILT item (fRes = item, fSrc = source element, fIndex = index); false items are dropped. Not applied to static_array
-  "static_array": { "length": 3, "items": { "0": <Field>, ... } }   // fixed-length array, key = index
+  "static_array": { "length": 3, "items": { "0": <Field>, ... } },  // fixed-length array, key = index
+  "table": { "header_rows": 0, "no_header": false, "as_arrays": false, "header_separator": " " }   // HTML/xpath only: root_path selects a <table>, every row becomes {"<header text>": "<cell>"} (or array) and item_config uses JSON paths on it, e.g. "Name", "Price Min"; colspan/rowspan expanded, header from <thead> or leading <th> rows
 }
 
 GeneratedFieldConfig (pick one):
@@ -135,7 +155,15 @@ Good for auth tokens shared across requests.
 
 ## limits (top level, optional)
 
-{ "host_request_limiter": {"example.com": 5}, "chromium_instance": 1, "docker_containers": 1, "playwright_instance": 1 }
+{
+  "host_request_limiter": {"example.com": 5},          // concurrent requests per host
+  "chromium_instance": 1, "docker_containers": 1, "playwright_instance": 1,   // concurrent browsers
+  "max_processes": 4,                                  // concurrent commands of the exec connector
+  "max_body_bytes": 52428800,                          // BYTES read by every connector; the read fails above it (connector truncate_body cuts instead)
+  "field_workers": 200,                                // goroutines resolving fields/array items in the whole process; when all are busy the field is resolved inline
+  "field_workers_per_item": 20,                        // fields/items resolved concurrently inside one object or array
+  "sequential_fields": false                           // true = resolve one by one in sorted key order, for debugging
+}   // every limit defaults to 0 = unlimited
 
 ## item.notifier_config (optional) — push the result somewhere after parsing
 
@@ -152,7 +180,8 @@ The parse result is still returned by the tool; the notifier additionally delive
   "telegram_bot": { "token": "{{{FromEnv=TG_TOKEN}}}", "users_id": [123], "pretty": true, "only_msg": false },
   "redis":        { "addr": "localhost:6379", "password": "", "db": 0, "channel": "fitter" },
   "file":         { "content": "{PL}\n", "file_name": "out.log", "path": "/tmp", "append": true },
-  "console":      { "only_result": true }    // prints to the server's stderr, visible in MCP client logs
+  "console":      { "only_result": true },   // prints to the server's stderr, visible in MCP client logs
+  "sql":          { "driver": "sqlite"|"postgres"|"mysql", "dsn": "/data/out.db", "table": "prices", "columns": [{"name": "sku", "path": "sku", "type": "string"|"int"|"float"|"bool"|"object"|"array"}], "key": ["sku"], "mode": "insert"|"upsert", "batch_size": 100 }   // table created if missing; with send_array_by_item every element is a row, written in batched transactions; key becomes the primary key of the created table in both modes (insert fails on a duplicate key), upsert requires key
 }
 
 ## NOT available via MCP (service mode only)
@@ -6,8 +6,10 @@ import (
 	"github.com/PxyUp/fitter/pkg/config"
 	"github.com/PxyUp/fitter/pkg/logger"
 	"github.com/PxyUp/fitter/pkg/parser"
+	"github.com/PxyUp/fitter/pkg/processor"
 	"github.com/PxyUp/fitter/pkg/registry"
 	"github.com/google/uuid"
+	"iter"
 )
 
 func Parse(item *config.Item, limits *config.Limits, refMap config.RefMap, input builder.Interfacable, log logger.Logger) (*parser.ParseResult, error) {
@@ -18,6 +20,34 @@ func Parse(item *config.Item, limits *config.Limits, refMap config.RefMap, input
 // headless browsers, docker containers), so cancelling it aborts in-flight
 // fetches. Reference prefetching is not tied to ctx.
 func ParseCtx(ctx context.Context, item *config.Item, limits *config.Limits, refMap config.RefMap, input builder.Interfacable, log logger.Logger) (*parser.ParseResult, error) {
+	return newProcessor(item, limits, refMap, log).Process(ctx, input)
+}
+
+// Stream is like Parse for large arrays: items are yielded one by one as soon
+// as they are built instead of materialising the whole result. Non array
+// models yield their single value. Breaking out of the loop stops the parsing;
+// an error is yielded once as the last element. Notifiers are not called
+func Stream(item *config.Item, limits *config.Limits, refMap config.RefMap, input builder.Interfacable, log logger.Logger) iter.Seq2[builder.Interfacable, error] {
+	return StreamCtx(context.Background(), item, limits, refMap, input, log)
+}
+
+// StreamCtx is Stream with ctx propagated to all connectors like in ParseCtx
+func StreamCtx(ctx context.Context, item *config.Item, limits *config.Limits, refMap config.RefMap, input builder.Interfacable, log logger.Logger) iter.Seq2[builder.Interfacable, error] {
+	return func(yield func(builder.Interfacable, error) bool) {
+		stopped := false
+		err := newProcessor(item, limits, refMap, log).Stream(ctx, input, func(value builder.Interfacable) bool {
+			if !yield(value, nil) {
+				stopped = true
+			}
+			return !stopped
+		})
+		if err != nil && !stopped {
+			yield(nil, err)
+		}
+	}
+}
+
+func newProcessor(item *config.Item, limits *config.Limits, refMap config.RefMap, log logger.Logger) processor.Processor {
 	cfg := &config.CliItem{
 		Item:       item,
 		Limits:     limits,
@@ -28,5 +58,5 @@ func ParseCtx(ctx context.Context, item *config.Item, limits *config.Limits, ref
 	if log == nil {
 		log = logger.Null
 	}
-	return registry.FromItem(cfg, log).Get(name).Process(ctx, input)
+	return registry.FromItem(cfg, log).Get(name)
 }
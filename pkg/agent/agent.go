@@ -275,6 +275,22 @@ func parseResult(text string) (*Result, error) {
 	}, nil
 }
 
+// urlConnector is the name of the configured connector which fetches the url
+// of the connector_config
+func urlConnector(connector *config.ConnectorConfig) string {
+	switch {
+	case connector.GraphQLConfig != nil:
+		return "graphql_config"
+	case connector.SSEConfig != nil:
+		return "sse_config"
+	case connector.WebSocketConfig != nil:
+		return "websocket_config"
+	case connector.SitemapConfig != nil:
+		return "sitemap_config"
+	}
+	return ""
+}
+
 // ValidateConfig catches the structural mistakes that would otherwise surface
 // as a confusing nil dereference or an empty result at execution time.
 func ValidateConfig(cfg *config.CliItem) error {
@@ -288,20 +304,35 @@ func ValidateConfig(cfg *config.CliItem) error {
 	}
 
 	switch connector.ResponseType {
-	case config.Json, config.HTML, config.XML, config.XPath, config.PDF:
+	case config.Json, config.HTML, config.XML, config.XPath, config.PDF, config.Metadata, config.Feed:
 	case "":
 		return errors.New(`"connector_config" is missing "response_type"`)
 	default:
-		return fmt.Errorf(`"connector_config" has invalid "response_type" %q (want json, HTML, XML, xpath or pdf)`, connector.ResponseType)
+		return fmt.Errorf(`"connector_config" has invalid "response_type" %q (want json, HTML, XML, xpath, pdf, metadata or feed)`, connector.ResponseType)
+	}
+	if connector.Readability && connector.ResponseType != config.HTML {
+		return fmt.Errorf(`"connector_config" with "readability" needs "response_type" HTML, got %q`, connector.ResponseType)
+	}
+	if connector.ServerConfig != nil && connector.ServerConfig.Transport != nil {
+		switch redirect := connector.ServerConfig.Transport.Redirect; redirect {
+		case "", config.RedirectFollow, config.RedirectNone, config.RedirectSameHost:
+		default:
+			return fmt.Errorf(`"server_config.transport" has invalid "redirect" %q (want follow, none or same_host)`, redirect)
+		}
 	}
 
+	if name := urlConnector(connector); name != "" && connector.Url == "" {
+		return fmt.Errorf(`"connector_config" with "%s" needs a "url"`, name)
+	}
 	if connector.Url == "" &&
 		connector.StaticConfig == nil &&
 		connector.FileConfig == nil &&
 		connector.IntSequenceConfig == nil &&
 		connector.ReferenceConfig == nil &&
-		connector.PluginConnectorConfig == nil {
-		return errors.New(`"connector_config" needs a "url" or one of static_config/file_config/int_sequence_config/reference_config/plugin_connector_config`)
+		connector.PluginConnectorConfig == nil &&
+		connector.SQLConfig == nil &&
+		connector.ExecConfig == nil {
+		return errors.New(`"connector_config" needs a "url" or one of static_config/file_config/int_sequence_config/reference_config/plugin_connector_config/sql_config/exec_config`)
 	}
 
 	model := cfg.Item.Model
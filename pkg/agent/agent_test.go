@@ -107,11 +107,41 @@ func TestValidateConfig(t *testing.T) {
 			config:  `{"item": {"connector_config": {"response_type": "yaml", "url": "https://x.dev"}, "model": {"base_field": {"type": "string"}}}}`,
 			wantErr: `invalid "response_type"`,
 		},
+		{
+			name:    "readability without HTML",
+			config:  `{"item": {"connector_config": {"response_type": "json", "readability": true, "url": "https://x.dev"}, "model": {"base_field": {"type": "string"}}}}`,
+			wantErr: `"readability" needs "response_type" HTML`,
+		},
+		{
+			name:    "unknown redirect policy",
+			config:  `{"item": {"connector_config": {"response_type": "json", "url": "https://x.dev", "server_config": {"transport": {"redirect": "never"}}}, "model": {"base_field": {"type": "string"}}}}`,
+			wantErr: `invalid "redirect" "never"`,
+		},
 		{
 			name:    "no connector source",
 			config:  `{"item": {"connector_config": {"response_type": "json"}, "model": {"base_field": {"type": "string"}}}}`,
 			wantErr: `needs a "url"`,
 		},
+		{
+			name:    "graphql without url",
+			config:  `{"item": {"connector_config": {"response_type": "json", "graphql_config": {"query": "{ x }"}}, "model": {"base_field": {"type": "string"}}}}`,
+			wantErr: `"graphql_config" needs a "url"`,
+		},
+		{
+			name:    "sse without url",
+			config:  `{"item": {"connector_config": {"response_type": "json", "sse_config": {"duration": 5}}, "model": {"base_field": {"type": "string"}}}}`,
+			wantErr: `"sse_config" needs a "url"`,
+		},
+		{
+			name:    "websocket without url",
+			config:  `{"item": {"connector_config": {"response_type": "json", "websocket_config": {"duration": 5}}, "model": {"base_field": {"type": "string"}}}}`,
+			wantErr: `"websocket_config" needs a "url"`,
+		},
+		{
+			name:    "sitemap without url",
+			config:  `{"item": {"connector_config": {"response_type": "json", "sitemap_config": {"max_urls": 10}}, "model": {"base_field": {"type": "string"}}}}`,
+			wantErr: `"sitemap_config" needs a "url"`,
+		},
 		{
 			name:    "missing model",
 			config:  `{"item": {"connector_config": {"response_type": "json", "url": "https://x.dev"}}}`,
@@ -140,6 +170,36 @@ func TestValidateConfigAcceptsStaticConnector(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+// sql_config has no url, its dsn is the source
+func TestValidateConfigAcceptsSQLConnector(t *testing.T) {
+	cfg := `{"item": {"connector_config": {"response_type": "json", "sql_config": {"driver": "sqlite", "dsn": "/data/shop.db", "query": "SELECT 1"}}, "model": {"base_field": {"type": "string"}}}}`
+	_, err := parseResult(envelope(t, cfg))
+	assert.NoError(t, err)
+}
+
+// exec_config has no url, the command is the source
+func TestValidateConfigAcceptsExecConnector(t *testing.T) {
+	cfg := `{"item": {"connector_config": {"response_type": "json", "exec_config": {"command": "echo", "args": ["{}"]}}, "model": {"base_field": {"type": "string"}}}}`
+	_, err := parseResult(envelope(t, cfg))
+	assert.NoError(t, err)
+}
+
+// connectors without own source config need the url of the connector_config
+func TestValidateConfigAcceptsURLConnectors(t *testing.T) {
+	for name, connector := range map[string]string{
+		"graphql":   `"graphql_config": {"query": "{ x }"}`,
+		"sse":       `"sse_config": {"duration": 5}`,
+		"websocket": `"websocket_config": {"duration": 5}`,
+		"sitemap":   `"sitemap_config": {"max_urls": 10}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := `{"item": {"connector_config": {"response_type": "json", "url": "https://x.dev", ` + connector + `}, "model": {"base_field": {"type": "string"}}}}`
+			_, err := parseResult(envelope(t, cfg))
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func TestValidateConfigConditions(t *testing.T) {
 	connector := `"connector_config": {"response_type": "json", "url": "https://x.dev"}`
 
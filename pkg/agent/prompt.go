@@ -24,7 +24,7 @@ config and apply only the requested change - keep every other field as it was.
 
 ## ConnectorConfig
 {
-  "response_type": "json" | "HTML" | "xpath" | "XML" | "pdf",
+  "response_type": "json" | "HTML" | "xpath" | "XML" | "pdf" | "metadata" | "feed",
   "url": "https://...",
   "attempts": 3,
   "server_config": { "method": "GET", "headers": {...}, "body": "...", "timeout": 30 },
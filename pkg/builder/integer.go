@@ -0,0 +1,79 @@
+package builder
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/PxyUp/fitter/pkg/config"
+)
+
+// integer keeps int/int64 values exact, float64 loses precision above 2^53
+type integer struct {
+	value int64
+}
+
+func (i *integer) ToInterface() interface{} {
+	return i.value
+}
+
+var (
+	_ Interfacable = &integer{}
+)
+
+func Int(value int64) *integer {
+	return &integer{
+		value: value,
+	}
+}
+
+func (i *integer) IsEmpty() bool {
+	return false
+}
+
+func (i *integer) ToJson() string {
+	return strconv.FormatInt(i.value, 10)
+}
+
+func (i *integer) Raw() json.RawMessage {
+	return toRaw(i.value)
+}
+
+// ToInteger converts the float with the rounding mode; without rounding non
+// integral values are rejected, as well as values out of the int64 range
+func ToInteger(value float64, rounding config.Rounding) (int64, bool) {
+	switch rounding {
+	case config.RoundHalf:
+		value = math.Round(value)
+	case config.RoundFloor:
+		value = math.Floor(value)
+	case config.RoundCeil:
+		value = math.Ceil(value)
+	case config.RoundTruncate:
+		value = math.Trunc(value)
+	}
+
+	if value != math.Trunc(value) || value < math.MinInt64 || value >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(value), true
+}
+
+// IntFromString parses the integer exactly, falling back to the float
+// notation ("12.0", "1e3") converted with the rounding mode
+func IntFromString(text string, rounding config.Rounding) Interfacable {
+	if value, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return Int(value)
+	}
+
+	floatValue, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return NullValue
+	}
+
+	value, ok := ToInteger(floatValue, rounding)
+	if !ok {
+		return NullValue
+	}
+	return Int(value)
+}
@@ -0,0 +1,37 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntExact(t *testing.T) {
+	num := builder.IntFromString("9007199254740993", "")
+	assert.Equal(t, "9007199254740993", num.ToJson())
+	assert.Equal(t, int64(9007199254740993), num.ToInterface())
+	assert.Equal(t, num.Raw(), builder.ToJsonable(num.Raw()).Raw())
+}
+
+func TestToInteger(t *testing.T) {
+	for _, tc := range []struct {
+		value    float64
+		rounding config.Rounding
+		expected int64
+		ok       bool
+	}{
+		{value: 12, expected: 12, ok: true},
+		{value: 12.7, ok: false},
+		{value: 12.7, rounding: config.RoundHalf, expected: 13, ok: true},
+		{value: 12.7, rounding: config.RoundFloor, expected: 12, ok: true},
+		{value: 12.2, rounding: config.RoundCeil, expected: 13, ok: true},
+		{value: -12.7, rounding: config.RoundTruncate, expected: -12, ok: true},
+		{value: 1e20, rounding: config.RoundHalf, ok: false},
+	} {
+		value, ok := builder.ToInteger(tc.value, tc.rounding)
+		assert.Equal(t, tc.ok, ok)
+		assert.Equal(t, tc.expected, value)
+	}
+}
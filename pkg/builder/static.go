@@ -47,7 +47,11 @@ func Static(cfg *StaticCfg) *static {
 		return &static{
 			value: Bool(boolValue),
 		}
-	case config.Float, config.Int, config.Float64, config.Int64:
+	case config.Int, config.Int64:
+		return &static{
+			value: IntFromString(cfg.Value, ""),
+		}
+	case config.Float, config.Float64:
 		float32Value, err := strconv.ParseFloat(cfg.Value, 64)
 		if err != nil {
 			return &static{
@@ -3,6 +3,7 @@ package builder
 import (
 	"encoding/json"
 	"github.com/tidwall/gjson"
+	"strconv"
 )
 
 func toJson(result gjson.Result) Interfacable {
@@ -37,6 +38,10 @@ func toJson(result gjson.Result) Interfacable {
 	case gjson.Null:
 		return NullValue
 	case gjson.Number:
+		// integer literals stay exact
+		if value, err := strconv.ParseInt(result.Raw, 10, 64); err == nil {
+			return Int(value)
+		}
 		return Number(result.Num)
 	}
 	return NullValue
@@ -17,6 +17,10 @@ const (
 	XML   ParserType = "XML"
 	XPath ParserType = "xpath"
 	PDF   ParserType = "pdf"
+	// Metadata exposes JSON-LD, microdata, meta tags and links of the HTML body as json
+	Metadata ParserType = "metadata"
+	// Feed normalizes RSS, Atom and JSON Feed bodies into one json shape
+	Feed ParserType = "feed"
 )
 
 type HostRequestLimiter map[string]int64
@@ -33,6 +37,21 @@ type Limits struct {
 	ChromiumInstance   uint32             `yaml:"chromium_instance" json:"chromium_instance"`
 	DockerContainers   uint32             `yaml:"docker_containers" json:"docker_containers"`
 	PlaywrightInstance uint32             `yaml:"playwright_instance" json:"playwright_instance"`
+	// MaxProcesses caps the commands run concurrently by the exec connector
+	MaxProcesses uint32 `yaml:"max_processes" json:"max_processes"`
+	// MaxBodyBytes caps the body read by every connector, the read fails when
+	// it's exceeded; a connector can override it with its own max_body_bytes
+	MaxBodyBytes int64 `yaml:"max_body_bytes" json:"max_body_bytes"`
+
+	// FieldWorkers caps the goroutines resolving fields and array items across
+	// the whole process; when no worker is free the field is resolved inline
+	FieldWorkers uint32 `yaml:"field_workers" json:"field_workers"`
+	// FieldWorkersPerItem caps the fields/items resolved concurrently inside one
+	// object or array
+	FieldWorkersPerItem uint32 `yaml:"field_workers_per_item" json:"field_workers_per_item"`
+	// SequentialFields resolves fields and array items one by one in a
+	// deterministic order (object keys sorted), useful for debugging
+	SequentialFields bool `yaml:"sequential_fields" json:"sequential_fields"`
 }
 
 type Config struct {
@@ -79,6 +98,22 @@ type ArrayConfig struct {
 	ItemCondition string `json:"item_condition" yaml:"item_condition"`
 
 	StaticConfig *StaticArrayConfig `json:"static_array"  yaml:"static_array"`
+
+	// Table converts the <table> at RootPath (HTML and xpath parsers) into
+	// rows, item_config is resolved with json paths against every row
+	Table *TableConfig `json:"table" yaml:"table"`
+}
+
+type TableConfig struct {
+	// HeaderRows is the amount of header rows; when 0 the rows of <thead> or
+	// the leading rows made of <th> only are used
+	HeaderRows int `json:"header_rows" yaml:"header_rows"`
+	// NoHeader keys the cells by column index ("0", "1", ...)
+	NoHeader bool `json:"no_header" yaml:"no_header"`
+	// AsArrays makes every row an array of cell texts, header rows are skipped
+	AsArrays bool `json:"as_arrays" yaml:"as_arrays"`
+	// HeaderSeparator joins the texts of multi-row headers, " " by default
+	HeaderSeparator string `json:"header_separator" yaml:"header_separator"`
 }
 
 type StaticArrayConfig struct {
@@ -100,6 +135,17 @@ type ConnectorConfig struct {
 
 	NullOnError bool `yaml:"null_on_error" json:"null_on_error"`
 
+	// MaxBodyBytes overrides the global limit of the body read by the connector
+	MaxBodyBytes int64 `yaml:"max_body_bytes" json:"max_body_bytes"`
+	// TruncateBody cuts the body at the limit instead of failing
+	TruncateBody bool `yaml:"truncate_body" json:"truncate_body"`
+	// Charset of HTML/XPath/XML bodies (windows-1251, shift_jis, gbk...),
+	// detected from the headers and the document when empty
+	Charset string `yaml:"charset" json:"charset"`
+	// Readability makes the HTML parser expose the main content of the article
+	// page as json (title, byline, content, lead image...)
+	Readability bool `yaml:"readability" json:"readability"`
+
 	StaticConfig          *StaticConnectorConfig      `json:"static_config" yaml:"static_config"`
 	IntSequenceConfig     *IntSequenceConnectorConfig `json:"int_sequence_config" yaml:"int_sequence_config"`
 	ServerConfig          *ServerConnectorConfig      `json:"server_config" yaml:"server_config"`
@@ -107,11 +153,163 @@ type ConnectorConfig struct {
 	PluginConnectorConfig *PluginConnectorConfig      `json:"plugin_connector_config" yaml:"plugin_connector_config"`
 	ReferenceConfig       *ReferenceConnectorConfig   `yaml:"reference_config" json:"reference_config"`
 	FileConfig            *FileConnectorConfig        `json:"file_config" yaml:"file_config"`
+	GraphQLConfig         *GraphQLConnectorConfig     `json:"graphql_config" yaml:"graphql_config"`
+	SSEConfig             *SSEConnectorConfig         `json:"sse_config" yaml:"sse_config"`
+	WebSocketConfig       *WebSocketConnectorConfig   `json:"websocket_config" yaml:"websocket_config"`
+	SQLConfig             *SQLConnectorConfig         `json:"sql_config" yaml:"sql_config"`
+	ExecConfig            *ExecConnectorConfig        `json:"exec_config" yaml:"exec_config"`
+	SitemapConfig         *SitemapConnectorConfig     `json:"sitemap_config" yaml:"sitemap_config"`
+
+	// Archive decompresses or extracts the body of any connector before parsing
+	Archive *ArchiveConfig `json:"archive" yaml:"archive"`
+}
+
+// ExecConnectorConfig runs a command (without shell) and returns its stdout
+type ExecConnectorConfig struct {
+	Command string   `json:"command" yaml:"command"`
+	Args    []string `json:"args" yaml:"args"`
+	// Env is added to the environment of the fitter process
+	Env   map[string]string `json:"env" yaml:"env"`
+	Dir   string            `json:"dir" yaml:"dir"`
+	Stdin string            `json:"stdin" yaml:"stdin"`
+	// Timeout[sec] after which the process tree is killed, default 60
+	Timeout uint32 `json:"timeout" yaml:"timeout"`
+}
+
+// SitemapConnectorConfig walks the sitemap of the connector url (sitemap.xml,
+// sitemap index, gzipped or text sitemap, or robots.txt with Sitemap: lines)
+// and returns the url entries as json array of {loc, lastmod, changefreq, priority}
+type SitemapConnectorConfig struct {
+	// Filter is a regexp the loc of the entry must match
+	Filter string `json:"filter" yaml:"filter"`
+	// LastmodAfter keeps entries modified after the date (RFC 3339 or 2006-01-02),
+	// supports placeholders; entries without lastmod are dropped
+	LastmodAfter string `json:"lastmod_after" yaml:"lastmod_after"`
+	// MaxDepth is the amount of nested sitemap levels followed below the
+	// first document, default 5
+	MaxDepth uint32 `json:"max_depth" yaml:"max_depth"`
+	// MaxURLs stops the walk after this amount of entries, 0 means no limit
+	MaxURLs uint32 `json:"max_urls" yaml:"max_urls"`
+	// Server holds the headers, timeout, proxy, oauth2, transport and cookie jar
+	// settings of the requests; method and body are ignored
+	Server *ServerConnectorConfig `json:"server" yaml:"server"`
+}
+
+type ArchiveFormat string
+
+const (
+	ArchiveAuto  ArchiveFormat = "auto"
+	ArchiveGzip  ArchiveFormat = "gzip"
+	ArchiveZstd  ArchiveFormat = "zstd"
+	ArchiveZip   ArchiveFormat = "zip"
+	ArchiveTar   ArchiveFormat = "tar"
+	ArchiveTarGz ArchiveFormat = "tar.gz"
+)
+
+type ArchiveConfig struct {
+	// Format is detected from the body when empty or auto
+	Format ArchiveFormat `json:"format" yaml:"format"`
+	// Member selects the zip/tar entry by name or glob, first match wins;
+	// the first file is used when empty
+	Member string `json:"member" yaml:"member"`
+	// MaxSize[bytes] of the decompressed body, default 100MB
+	MaxSize int64 `json:"max_size" yaml:"max_size"`
+}
+
+type SQLDriver string
+
+const (
+	SQLite   SQLDriver = "sqlite"
+	Postgres SQLDriver = "postgres"
+	MySQL    SQLDriver = "mysql"
+)
+
+// SQLConnectorConfig runs a query and returns the rows as json array of objects
+type SQLConnectorConfig struct {
+	Driver SQLDriver `json:"driver" yaml:"driver"`
+	// DSN supports placeholders, for example {{{FromEnv=DATABASE_URL}}}
+	DSN string `json:"dsn" yaml:"dsn"`
+	// Query is sent as is, values must be passed through Args
+	Query string `json:"query" yaml:"query"`
+	// Args are the bind parameters of the query, typed like static fields;
+	// their values support placeholders and are never interpolated into the query
+	Args []*StaticGeneratedFieldConfig `json:"args" yaml:"args"`
+}
+
+// SSEConnectorConfig reads the Server-Sent Events stream of the connector url
+// and returns the collected messages as json array
+type SSEConnectorConfig struct {
+	Method  string            `json:"method" yaml:"method"`
+	Headers map[string]string `json:"headers" yaml:"headers"`
+	// Body is an optional subscribe message sent with the request
+	Body string `json:"body" yaml:"body"`
+	// Event collects only the events with this name, by default all events are collected
+	Event string `json:"event" yaml:"event"`
+
+	// MaxMessages stops the collection after this amount of messages
+	MaxMessages uint32 `json:"max_messages" yaml:"max_messages"`
+	// Duration[sec] of the collection window, default 60
+	Duration uint32 `json:"duration" yaml:"duration"`
+	// Until is an expression evaluated on every message (fRes), the collection
+	// stops after the first matching message
+	Until string `json:"until" yaml:"until"`
+}
+
+// WebSocketConnectorConfig reads the messages of the WebSocket at the
+// connector url and returns the collected messages as json array
+type WebSocketConnectorConfig struct {
+	Headers map[string]string `json:"headers" yaml:"headers"`
+	// Origin header of the handshake, default is the http(s) version of the url
+	Origin string `json:"origin" yaml:"origin"`
+	// Subscribe is an optional message sent right after the connection is open
+	Subscribe    string          `json:"subscribe" yaml:"subscribe"`
+	SubscribeRaw json.RawMessage `json:"subscribe_raw" yaml:"subscribe_raw"`
+
+	// MaxMessages stops the collection after this amount of messages
+	MaxMessages uint32 `json:"max_messages" yaml:"max_messages"`
+	// Duration[sec] of the collection window, default 60
+	Duration uint32 `json:"duration" yaml:"duration"`
+	// Until is an expression evaluated on every message (fRes), the collection
+	// stops after the first matching message
+	Until string `json:"until" yaml:"until"`
+}
+
+// GraphQLConnectorConfig posts a GraphQL query to the connector url; the
+// result is the "data" of the response (or the collected nodes with pagination)
+type GraphQLConnectorConfig struct {
+	// Query is sent as is, values must be passed through Variables
+	Query         string `json:"query" yaml:"query"`
+	OperationName string `json:"operation_name" yaml:"operation_name"`
+	// Variables are typed like static fields, their values support placeholders
+	Variables map[string]*StaticGeneratedFieldConfig `json:"variables" yaml:"variables"`
+	// Server holds the headers, timeout, proxy, oauth2, transport and cookie jar
+	// settings of the request; method and body are ignored
+	Server     *ServerConnectorConfig   `json:"server" yaml:"server"`
+	Pagination *GraphQLPaginationConfig `json:"pagination" yaml:"pagination"`
+}
+
+// GraphQLPaginationConfig follows Relay style cursor pagination
+type GraphQLPaginationConfig struct {
+	// PageInfoPath is the path of the pageInfo object inside "data", for example "search.pageInfo"
+	PageInfoPath string `json:"page_info_path" yaml:"page_info_path"`
+	// NodesPath is the path of the nodes array inside "data", for example
+	// "search.nodes" or "search.edges.#.node" for connections without nodes
+	NodesPath string `json:"nodes_path" yaml:"nodes_path"`
+	// CursorVariable receives pageInfo.endCursor for the next page, default is "after"
+	CursorVariable string `json:"cursor_variable" yaml:"cursor_variable"`
+	// MaxPages limits the amount of requested pages, 0 means no limit
+	MaxPages uint32 `json:"max_pages" yaml:"max_pages"`
 }
 
 type FileConnectorConfig struct {
 	Path          string `yaml:"path" json:"path"`
 	UseFormatting bool   `yaml:"use_formatting" json:"use_formatting"`
+	// Glob switches to batch mode: Path is the directory and every file matching
+	// the pattern is parsed with the model; the results are collected into an
+	// array of {file_name, path, mod_time, result}
+	Glob string `yaml:"glob" json:"glob"`
+	// Recursive includes the files of the subdirectories in batch mode
+	Recursive bool `yaml:"recursive" json:"recursive"`
 }
 
 type IntSequenceConnectorConfig struct {
@@ -193,9 +391,68 @@ type ServerConnectorConfig struct {
 	Timeout     uint32            `yaml:"timeout" json:"timeout"`
 	JsonRawBody json.RawMessage   `json:"json_raw_body" yaml:"json_raw_body"`
 	Body        string            `yaml:"body" json:"body"`
+	// Form is sent as application/x-www-form-urlencoded body
+	Form map[string]string `yaml:"form" json:"form"`
+	// Multipart is sent as multipart/form-data body, it has priority over Form
+	Multipart []*MultipartPart `yaml:"multipart" json:"multipart"`
+
+	Proxy     *ProxyConfig     `yaml:"proxy" json:"proxy"`
+	OAuth2    *OAuth2Config    `yaml:"oauth2" json:"oauth2"`
+	Transport *TransportConfig `yaml:"transport" json:"transport"`
+	CookieJar *CookieJarConfig `yaml:"cookie_jar" json:"cookie_jar"`
+}
 
-	Proxy  *ProxyConfig  `yaml:"proxy" json:"proxy"`
-	OAuth2 *OAuth2Config `yaml:"oauth2" json:"oauth2"`
+// MultipartPart is a value part or, when File is set, a file part of a
+// multipart/form-data body
+type MultipartPart struct {
+	Name  string `yaml:"name" json:"name"`
+	Value string `yaml:"value" json:"value"`
+	// File is the path of the uploaded file
+	File string `yaml:"file" json:"file"`
+	// FileName defaults to the base name of File
+	FileName string `yaml:"file_name" json:"file_name"`
+	// ContentType of the file part, detected from the extension or content by default
+	ContentType string `yaml:"content_type" json:"content_type"`
+}
+
+// CookieJarConfig keeps the cookies set by responses and sends them back on
+// the next requests; connectors with the same jar name share the cookies
+type CookieJarConfig struct {
+	// Name identifies the shared jar, defaults to File (or "default" when both are empty)
+	Name string `yaml:"name" json:"name"`
+	// File is an optional path for persisting the jar between runs in the
+	// playwright storage state format; it is loaded on the first use and
+	// written back after every request
+	File string `yaml:"file" json:"file"`
+	// ImportStorageState is an optional playwright storage state file (for
+	// example created by "fitter_cli browser-login") loaded once into the jar
+	ImportStorageState string `yaml:"import_storage_state" json:"import_storage_state"`
+}
+
+type RedirectPolicy string
+
+const (
+	RedirectFollow   RedirectPolicy = "follow"
+	RedirectNone     RedirectPolicy = "none"
+	RedirectSameHost RedirectPolicy = "same_host"
+)
+
+// TransportConfig tunes the pooled HTTP transport; requests with equal
+// settings (and proxy) share one transport and reuse its connections
+type TransportConfig struct {
+	MaxIdleConns        int  `yaml:"max_idle_conns" json:"max_idle_conns"`
+	MaxIdleConnsPerHost int  `yaml:"max_idle_conns_per_host" json:"max_idle_conns_per_host"`
+	DisableHTTP2        bool `yaml:"disable_http2" json:"disable_http2"`
+	// CAFile is a PEM bundle trusted in addition to the system roots
+	CAFile string `yaml:"ca_file" json:"ca_file"`
+	// CertFile and KeyFile are the PEM client certificate and key for mTLS
+	CertFile           string `yaml:"cert_file" json:"cert_file"`
+	KeyFile            string `yaml:"key_file" json:"key_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+	// Redirect is "follow" (default), "none" (the redirect response is the
+	// result) or "same_host" (follow only redirects to the original host)
+	Redirect     RedirectPolicy `yaml:"redirect" json:"redirect"`
+	MaxRedirects uint32         `yaml:"max_redirects" json:"max_redirects"`
 }
 
 type OAuth2GrantType string
@@ -258,6 +515,39 @@ type NotifierConfig struct {
 	Http        *HttpConfig          `yaml:"http" json:"http"`
 	Redis       *RedisNotifierConfig `json:"redis" yaml:"redis"`
 	File        *FileStorageField    `json:"file" yaml:"file"`
+	SQL         *SQLNotifierConfig   `json:"sql" yaml:"sql"`
+}
+
+type SQLWriteMode string
+
+const (
+	SQLInsert SQLWriteMode = "insert"
+	SQLUpsert SQLWriteMode = "upsert"
+)
+
+// SQLNotifierConfig writes the result (or every array item with
+// send_array_by_item) as rows of a table, which is created if missing
+type SQLNotifierConfig struct {
+	Driver SQLDriver `json:"driver" yaml:"driver"`
+	// DSN supports placeholders, for example {{{FromEnv=DATABASE_URL}}}
+	DSN     string       `json:"dsn" yaml:"dsn"`
+	Table   string       `json:"table" yaml:"table"`
+	Columns []*SQLColumn `json:"columns" yaml:"columns"`
+	// Key lists the columns of the primary key of the created table, required
+	// for "upsert"; in "insert" mode a duplicate key fails the batch
+	Key []string `json:"key" yaml:"key"`
+	// Mode is "insert" (default) or "upsert" (update the row with the same key)
+	Mode SQLWriteMode `json:"mode" yaml:"mode"`
+	// BatchSize is the amount of rows written in one transaction, default 100
+	BatchSize uint32 `json:"batch_size" yaml:"batch_size"`
+}
+
+type SQLColumn struct {
+	Name string `json:"name" yaml:"name"`
+	// Path is the gjson path of the value in the result, empty path takes the whole result
+	Path string `json:"path" yaml:"path"`
+	// Type of the column: string (default), int, int64, float, float64, bool; object and array are stored as json text
+	Type FieldType `json:"type" yaml:"type"`
 }
 
 type HttpConfig struct {
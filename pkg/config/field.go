@@ -14,6 +14,12 @@ const (
 	Float64    FieldType = "float64"
 	HtmlString FieldType = "html"
 	RawString  FieldType = "raw_string"
+	// Money is an object with the amount and the ISO currency code
+	Money FieldType = "money"
+	// URL is resolved against the page url and <base href>
+	URL FieldType = "url"
+	// Markdown converts the selected html node or the html text of other parsers
+	Markdown FieldType = "markdown"
 
 	Array  FieldType = "array"
 	Object FieldType = "object"
@@ -33,6 +39,17 @@ type BaseField struct {
 
 	HTMLAttribute string `json:"html_attribute" yaml:"html_attribute"`
 
+	// Transforms clean the extracted text in order, before Regex and the type conversion
+	Transforms []*TransformConfig `json:"transforms" yaml:"transforms"`
+	// Regex is applied to the extracted text before the type conversion
+	Regex *RegexConfig `json:"regex" yaml:"regex"`
+	// Number enables lenient parsing of numeric and money types
+	Number *NumberConfig `json:"number" yaml:"number"`
+	// Rounding of non-integral values of int/int64 types, rejected (null) by default
+	Rounding Rounding `json:"rounding" yaml:"rounding"`
+	// URL configures the url type
+	URL *URLConfig `json:"url" yaml:"url"`
+
 	// Condition is evaluated against the extracted value (fRes/fResJson/fResRaw, fIndex);
 	// when false the field is omitted from the parent instead of producing null
 	Condition string `json:"condition" yaml:"condition"`
@@ -42,6 +59,79 @@ type BaseField struct {
 	FirstOf []*BaseField `json:"first_of" yaml:"first_of"`
 }
 
+type Rounding string
+
+const (
+	RoundHalf     Rounding = "round"
+	RoundFloor    Rounding = "floor"
+	RoundCeil     Rounding = "ceil"
+	RoundTruncate Rounding = "truncate"
+)
+
+type URLConfig struct {
+	// Base is the page url for connectors which don't know it (static, file)
+	Base string `json:"base" yaml:"base"`
+	// StripTracking removes utm_*, fbclid, gclid and other tracking query params
+	StripTracking bool `json:"strip_tracking" yaml:"strip_tracking"`
+	// StripParams are extra query params to remove
+	StripParams []string `json:"strip_params" yaml:"strip_params"`
+}
+
+type NumberConfig struct {
+	// Locale selects the decimal separator ("en" 1,234.56, "de" 1.234,56,
+	// "fr" 1 234,56); guessed from the text when empty
+	Locale string `json:"locale" yaml:"locale"`
+	// Magnitude applies K/M/B suffixes ("1.2K" -> 1200)
+	Magnitude bool `json:"magnitude" yaml:"magnitude"`
+	// Currency is the ISO code of money values without currency in the text
+	Currency string `json:"currency" yaml:"currency"`
+}
+
+type RegexConfig struct {
+	Pattern string `json:"pattern" yaml:"pattern"`
+	// Group selects the capture group by index ("1") or name ("price"), the
+	// whole match by default
+	Group string `json:"group" yaml:"group"`
+	// All returns every match as array
+	All bool `json:"all" yaml:"all"`
+	// Replace is a template expanded for the match instead of the group ("$1.$2", "${price}")
+	Replace string `json:"replace" yaml:"replace"`
+}
+
+type TransformType string
+
+const (
+	TransformTrim               TransformType = "trim"
+	TransformLower              TransformType = "lower"
+	TransformUpper              TransformType = "upper"
+	TransformCollapseWhitespace TransformType = "collapse_whitespace"
+	TransformStripTags          TransformType = "strip_tags"
+	TransformReplace            TransformType = "replace"
+	TransformRegexReplace       TransformType = "regex_replace"
+	TransformSplit              TransformType = "split"
+	TransformJoin               TransformType = "join"
+	TransformHTMLUnescape       TransformType = "html_unescape"
+	TransformURLDecode          TransformType = "url_decode"
+	TransformBase64Decode       TransformType = "base64_decode"
+	TransformSubstring          TransformType = "substring"
+	TransformDefault            TransformType = "default"
+)
+
+type TransformConfig struct {
+	Type TransformType `json:"type" yaml:"type"`
+	// Pattern is the searched text of replace and the regexp of regex_replace
+	Pattern     string `json:"pattern" yaml:"pattern"`
+	Replacement string `json:"replacement" yaml:"replacement"`
+	// Separator of split and join
+	Separator string `json:"separator" yaml:"separator"`
+	// Start and End of substring in characters, negative values count from
+	// the end; End 0 means till the end
+	Start int `json:"start" yaml:"start"`
+	End   int `json:"end" yaml:"end"`
+	// Value of default, used when the value is empty or not found
+	Value string `json:"value" yaml:"value"`
+}
+
 type FormattedFieldConfig struct {
 	Template string `yaml:"template" json:"template"`
 }
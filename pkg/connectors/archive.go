@@ -0,0 +1,204 @@
+package connectors
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/utils"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	defaultArchiveMaxSize = 100 << 20
+	tarHeaderSize         = 512
+)
+
+var (
+	errArchiveTooLarge = errors.New("decompressed body exceeds max size")
+	errArchiveMember   = errors.New("archive member not found")
+	errArchiveFormat   = errors.New("unsupported archive format")
+
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	zipMagic  = []byte("PK\x03\x04")
+	// empty zip contains only the end of central directory record
+	zipEmptyMagic = []byte("PK\x05\x06")
+)
+
+type archiveConnector struct {
+	original Connector
+	cfg      *config.ArchiveConfig
+}
+
+func (a *archiveConnector) Get(ctx context.Context, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) ([]byte, error) {
+	body, err := a.original.Get(ctx, parsedValue, index, input)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSize := int64(defaultArchiveMaxSize)
+	if a.cfg.MaxSize > 0 {
+		maxSize = a.cfg.MaxSize
+	}
+
+	return extract(body, a.cfg.Format, utils.Format(a.cfg.Member, parsedValue, index, input), maxSize)
+}
+
+// WithArchive decompresses the body of the original connector or extracts
+// the selected member of the archive
+func WithArchive(original Connector, cfg *config.ArchiveConfig) Connector {
+	if cfg == nil {
+		return original
+	}
+
+	return &archiveConnector{
+		original: original,
+		cfg:      cfg,
+	}
+}
+
+func extract(body []byte, format config.ArchiveFormat, member string, maxSize int64) ([]byte, error) {
+	auto := format == "" || format == config.ArchiveAuto
+	if auto {
+		format = detectArchive(body)
+		if format == "" {
+			// plain body, nothing to decompress
+			return body, nil
+		}
+	}
+
+	switch format {
+	case config.ArchiveGzip, config.ArchiveTarGz:
+		reader, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		return fromStream(reader, format == config.ArchiveTarGz, auto, member, maxSize)
+	case config.ArchiveZstd:
+		decoder, err := zstd.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer decoder.Close()
+		return fromStream(decoder, false, auto, member, maxSize)
+	case config.ArchiveTar:
+		return fromTar(tar.NewReader(bytes.NewReader(body)), member, maxSize)
+	case config.ArchiveZip:
+		return fromZip(body, member, maxSize)
+	}
+
+	return nil, fmt.Errorf("%w: %q", errArchiveFormat, format)
+}
+
+func detectArchive(body []byte) config.ArchiveFormat {
+	switch {
+	case bytes.HasPrefix(body, gzipMagic):
+		return config.ArchiveGzip
+	case bytes.HasPrefix(body, zstdMagic):
+		return config.ArchiveZstd
+	case bytes.HasPrefix(body, zipMagic), bytes.HasPrefix(body, zipEmptyMagic):
+		return config.ArchiveZip
+	case isTarHeader(body):
+		return config.ArchiveTar
+	}
+	return ""
+}
+
+func isTarHeader(header []byte) bool {
+	return len(header) >= 262 && string(header[257:262]) == "ustar"
+}
+
+// fromStream reads the decompressed stream, in auto mode a tar inside
+// (tar.gz, tar.zst) is detected by its header
+func fromStream(reader io.Reader, isTar bool, auto bool, member string, maxSize int64) ([]byte, error) {
+	buffered := bufio.NewReaderSize(reader, tarHeaderSize)
+	if auto {
+		header, _ := buffered.Peek(tarHeaderSize)
+		isTar = isTarHeader(header)
+	}
+
+	if isTar {
+		return fromTar(tar.NewReader(buffered), member, maxSize)
+	}
+	return readLimited(buffered, maxSize)
+}
+
+func fromTar(reader *tar.Reader, member string, maxSize int64) ([]byte, error) {
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: %q", errArchiveMember, member)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Typeflag != tar.TypeReg || !matchMember(member, header.Name) {
+			continue
+		}
+		return readLimited(reader, maxSize)
+	}
+}
+
+func fromZip(body []byte, member string, maxSize int64) ([]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() || !matchMember(member, file.Name) {
+			continue
+		}
+		if file.UncompressedSize64 > uint64(maxSize) {
+			return nil, fmt.Errorf("%w: %d bytes", errArchiveTooLarge, maxSize)
+		}
+
+		content, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer content.Close()
+		// the size from the header can lie, limit the reader anyway
+		return readLimited(content, maxSize)
+	}
+
+	return nil, fmt.Errorf("%w: %q", errArchiveMember, member)
+}
+
+// matchMember matches the member name or glob, patterns without "/" are
+// matched against the base name of the entry
+func matchMember(pattern string, name string) bool {
+	if pattern == "" || pattern == name {
+		return true
+	}
+
+	if !strings.Contains(pattern, "/") {
+		name = path.Base(name)
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+func readLimited(reader io.Reader, maxSize int64) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(reader, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > maxSize {
+		return nil, fmt.Errorf("%w: %d bytes", errArchiveTooLarge, maxSize)
+	}
+	return body, nil
+}
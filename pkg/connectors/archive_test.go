@@ -0,0 +1,115 @@
+package connectors_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/connectors"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var archiveFiles = []struct {
+	name    string
+	content string
+}{
+	{name: "readme.txt", content: "hello"},
+	{name: "data/items.json", content: `[{"id": 1}]`},
+	{name: "data/prices.csv", content: "id,price\n1,10"},
+}
+
+func zipArchive(t *testing.T) []byte {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, file := range archiveFiles {
+		w, err := writer.Create(file.name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(file.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func tarArchive(t *testing.T) []byte {
+	var buf bytes.Buffer
+	writer := tar.NewWriter(&buf)
+	require.NoError(t, writer.WriteHeader(&tar.Header{Name: "data/", Typeflag: tar.TypeDir, Mode: 0o755}))
+	for _, file := range archiveFiles {
+		require.NoError(t, writer.WriteHeader(&tar.Header{Name: file.name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(file.content))}))
+		_, err := writer.Write([]byte(file.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func gzipBytes(t *testing.T, body []byte) []byte {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write(body)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func zstdBytes(t *testing.T, body []byte) []byte {
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	defer encoder.Close()
+	return encoder.EncodeAll(body, nil)
+}
+
+func archiveGet(t *testing.T, body []byte, cfg *config.ArchiveConfig) ([]byte, error) {
+	file := filepath.Join(t.TempDir(), "body")
+	require.NoError(t, os.WriteFile(file, body, 0o644))
+	return connectors.WithArchive(connectors.NewFile(&config.FileConnectorConfig{Path: file}), cfg).Get(context.Background(), nil, nil, nil)
+}
+
+func TestArchiveConnector(t *testing.T) {
+	for name, tc := range map[string]struct {
+		body     []byte
+		cfg      *config.ArchiveConfig
+		expected string
+	}{
+		"plain":            {body: []byte(`{"a": 1}`), cfg: &config.ArchiveConfig{}, expected: `{"a": 1}`},
+		"gzip":             {body: gzipBytes(t, []byte(`{"a": 1}`)), cfg: &config.ArchiveConfig{}, expected: `{"a": 1}`},
+		"zstd":             {body: zstdBytes(t, []byte(`{"a": 1}`)), cfg: &config.ArchiveConfig{Format: config.ArchiveZstd}, expected: `{"a": 1}`},
+		"zip by name":      {body: zipArchive(t), cfg: &config.ArchiveConfig{Member: "data/prices.csv"}, expected: "id,price\n1,10"},
+		"zip by glob":      {body: zipArchive(t), cfg: &config.ArchiveConfig{Member: "*.json"}, expected: `[{"id": 1}]`},
+		"zip first file":   {body: zipArchive(t), cfg: &config.ArchiveConfig{Format: config.ArchiveZip}, expected: "hello"},
+		"tar skips dirs":   {body: tarArchive(t), cfg: &config.ArchiveConfig{Member: "data/*"}, expected: `[{"id": 1}]`},
+		"tar.gz detected":  {body: gzipBytes(t, tarArchive(t)), cfg: &config.ArchiveConfig{Member: "*.csv"}, expected: "id,price\n1,10"},
+		"tar.gz explicit":  {body: gzipBytes(t, tarArchive(t)), cfg: &config.ArchiveConfig{Format: config.ArchiveTarGz, Member: "items.json"}, expected: `[{"id": 1}]`},
+		"tar.zst detected": {body: zstdBytes(t, tarArchive(t)), cfg: &config.ArchiveConfig{Member: "readme.txt"}, expected: "hello"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			body, err := archiveGet(t, tc.body, tc.cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(body))
+		})
+	}
+}
+
+func TestArchiveConnector_Errors(t *testing.T) {
+	_, err := archiveGet(t, zipArchive(t), &config.ArchiveConfig{Member: "missing.json"})
+	assert.ErrorContains(t, err, "archive member not found")
+
+	bomb := gzipBytes(t, bytes.Repeat([]byte("a"), 1<<20))
+	_, err = archiveGet(t, bomb, &config.ArchiveConfig{MaxSize: 1024})
+	assert.ErrorContains(t, err, "exceeds max size")
+
+	_, err = archiveGet(t, zipArchive(t), &config.ArchiveConfig{Member: "readme.txt", MaxSize: 3})
+	assert.ErrorContains(t, err, "exceeds max size")
+
+	_, err = archiveGet(t, []byte("plain"), &config.ArchiveConfig{Format: "rar"})
+	assert.ErrorContains(t, err, "unsupported archive format")
+}
@@ -0,0 +1,166 @@
+package connectors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/limitter"
+)
+
+var (
+	errBodyTooLarge = errors.New("body exceeds max_body_bytes")
+)
+
+type bodyLimitKey struct{}
+
+type bodyLimit struct {
+	maxBytes int64
+	truncate bool
+}
+
+type bodyLimitConnector struct {
+	original Connector
+	limit    bodyLimit
+}
+
+func (b *bodyLimitConnector) Get(ctx context.Context, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) ([]byte, error) {
+	return b.original.Get(context.WithValue(ctx, bodyLimitKey{}, b.limit), parsedValue, index, input)
+}
+
+// WithBodyLimit caps the body read by the original connector; maxBytes
+// overrides the global max_body_bytes, truncate cuts the body instead of failing
+func WithBodyLimit(original Connector, maxBytes int64, truncate bool) Connector {
+	if maxBytes <= 0 && !truncate {
+		return original
+	}
+
+	return &bodyLimitConnector{
+		original: original,
+		limit: bodyLimit{
+			maxBytes: maxBytes,
+			truncate: truncate,
+		},
+	}
+}
+
+func bodyLimitFromContext(ctx context.Context) bodyLimit {
+	limit, _ := ctx.Value(bodyLimitKey{}).(bodyLimit)
+	if limit.maxBytes <= 0 {
+		limit.maxBytes = limitter.MaxBodyBytes()
+	}
+	return limit
+}
+
+func (l bodyLimit) exceeded() error {
+	return fmt.Errorf("%w: more than %d bytes", errBodyTooLarge, l.maxBytes)
+}
+
+// apply checks the body which is already in memory
+func (l bodyLimit) apply(body []byte) ([]byte, error) {
+	if l.maxBytes <= 0 || int64(len(body)) <= l.maxBytes {
+		return body, nil
+	}
+	if l.truncate {
+		return body[:l.maxBytes], nil
+	}
+	return nil, l.exceeded()
+}
+
+// readBody reads at most one byte over the limit, so an endless body is
+// aborted without buffering it
+func readBody(ctx context.Context, reader io.Reader) ([]byte, error) {
+	limit := bodyLimitFromContext(ctx)
+	if limit.maxBytes <= 0 {
+		return io.ReadAll(reader)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(reader, limit.maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	return limit.apply(body)
+}
+
+// bodyBuffer collects the output of a process up to the limit; in truncate
+// mode the rest is discarded, so the process is never blocked on its output
+type bodyBuffer struct {
+	buf   bytes.Buffer
+	limit bodyLimit
+	size  int64
+}
+
+func newBodyBuffer(ctx context.Context) *bodyBuffer {
+	return &bodyBuffer{
+		limit: bodyLimitFromContext(ctx),
+	}
+}
+
+func (b *bodyBuffer) Write(p []byte) (int, error) {
+	b.size += int64(len(p))
+	if b.limit.maxBytes <= 0 {
+		return b.buf.Write(p)
+	}
+
+	if room := b.limit.maxBytes - int64(b.buf.Len()); room > 0 {
+		b.buf.Write(p[:min(room, int64(len(p)))])
+	}
+	if b.size > b.limit.maxBytes && !b.limit.truncate {
+		return 0, b.limit.exceeded()
+	}
+	return len(p), nil
+}
+
+func (b *bodyBuffer) Body() ([]byte, error) {
+	if b.limit.maxBytes > 0 && b.size > b.limit.maxBytes && !b.limit.truncate {
+		return nil, b.limit.exceeded()
+	}
+	return b.buf.Bytes(), nil
+}
+
+// jsonArray collects the elements of a json array body up to the limit; in
+// truncate mode the elements which don't fit are dropped, so the body stays
+// valid json
+type jsonArray struct {
+	limit    bodyLimit
+	elements []string
+	size     int64
+}
+
+func newJSONArray(ctx context.Context) *jsonArray {
+	return &jsonArray{
+		limit: bodyLimitFromContext(ctx),
+		size:  int64(len("[]")),
+	}
+}
+
+// add reports whether the element fit into the limit
+func (a *jsonArray) add(element string) (bool, error) {
+	size := int64(len(element))
+	if len(a.elements) > 0 {
+		size++
+	}
+	if a.limit.maxBytes > 0 && a.size+size > a.limit.maxBytes {
+		if a.limit.truncate {
+			return false, nil
+		}
+		return false, a.limit.exceeded()
+	}
+
+	a.size += size
+	a.elements = append(a.elements, element)
+	return true, nil
+}
+
+// fits reports whether the pending data of the next element is under the limit
+func (a *jsonArray) fits(size int) bool {
+	return a.limit.maxBytes <= 0 || a.size+int64(size) <= a.limit.maxBytes
+}
+
+func (a *jsonArray) bytes() []byte {
+	return []byte("[" + strings.Join(a.elements, ",") + "]")
+}
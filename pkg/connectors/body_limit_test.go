@@ -0,0 +1,138 @@
+package connectors_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/connectors"
+	"github.com/PxyUp/fitter/pkg/limitter"
+	"github.com/PxyUp/fitter/pkg/sqldb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func TestBodyLimitServer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/stream" {
+			// no content length: the limit is checked while reading
+			flusher := w.(http.Flusher)
+			for i := 0; i < 100; i++ {
+				_, _ = w.Write([]byte(strings.Repeat("a", 1024)))
+				flusher.Flush()
+			}
+			return
+		}
+		_, _ = w.Write([]byte(strings.Repeat("b", 4096)))
+	}))
+	defer srv.Close()
+
+	for name, tc := range map[string]struct {
+		path     string
+		truncate bool
+		err      bool
+	}{
+		"content length": {path: "/", err: true},
+		"stream":         {path: "/stream", err: true},
+		"truncate":       {path: "/stream", truncate: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			connector := connectors.WithBodyLimit(connectors.NewAPI(srv.URL+tc.path, &config.ServerConnectorConfig{
+				Method: http.MethodGet,
+			}, nil), 100, tc.truncate)
+
+			body, err := connector.Get(context.Background(), nil, nil, nil)
+			if tc.err {
+				assert.ErrorContains(t, err, "body exceeds max_body_bytes")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, strings.Repeat("a", 100), string(body))
+		})
+	}
+}
+
+func TestBodyLimitFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "body.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"value": "0123456789"}`), 0o644))
+	file := connectors.NewFile(&config.FileConnectorConfig{Path: path})
+
+	limitter.ReplaceLimits(&config.Limits{MaxBodyBytes: 10})
+	defer limitter.ReplaceLimits(nil)
+
+	_, err := file.Get(context.Background(), nil, nil, nil)
+	assert.ErrorContains(t, err, "more than 10 bytes")
+
+	// connector limit overrides the global one
+	body, err := connectors.WithBodyLimit(file, 100, false).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"value": "0123456789"}`, string(body))
+
+	body, err = connectors.WithBodyLimit(file, 0, true).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"value": `, string(body))
+}
+
+func TestBodyLimitStreams(t *testing.T) {
+	events := []string{
+		"data: {\"n\": 1}\n\n",
+		"data: {\"n\": 2}\n\n",
+		"data: " + strings.Repeat("x", 1024) + "\n\n",
+	}
+	srv := sseServer(t, events)
+	defer srv.Close()
+
+	sse := connectors.NewSSE(srv.URL, &config.SSEConnectorConfig{Duration: 5})
+	body, err := connectors.WithBodyLimit(sse, 100, true).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"n": 1}, {"n": 2}]`, string(body))
+
+	_, err = connectors.WithBodyLimit(sse, 100, false).Get(context.Background(), nil, nil, nil)
+	assert.ErrorContains(t, err, "body exceeds max_body_bytes")
+
+	ws := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		_ = websocket.Message.Send(conn, `{"n": 1}`)
+		_ = websocket.Message.Send(conn, strings.Repeat("x", 1024))
+		time.Sleep(2 * time.Second)
+	}))
+	defer ws.Close()
+
+	socket := connectors.NewWebSocket("ws"+strings.TrimPrefix(ws.URL, "http"), &config.WebSocketConnectorConfig{Duration: 5})
+	body, err = connectors.WithBodyLimit(socket, 100, true).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"n": 1}]`, string(body))
+
+	_, err = connectors.WithBodyLimit(socket, 100, false).Get(context.Background(), nil, nil, nil)
+	assert.ErrorContains(t, err, "body exceeds max_body_bytes")
+}
+
+func TestBodyLimitSQL(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "rows.db")
+	db, err := sqldb.Open(config.SQLite, dsn)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE items (id INTEGER PRIMARY KEY)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO items (id) VALUES (1), (2), (3), (4), (5)`)
+	require.NoError(t, err)
+
+	sql := connectors.NewSQL(&config.SQLConnectorConfig{
+		Driver: config.SQLite,
+		DSN:    dsn,
+		Query:  `SELECT id FROM items ORDER BY id`,
+	})
+
+	// rows are cut at the boundary, the body stays a valid array
+	body, err := connectors.WithBodyLimit(sql, 30, true).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"id": 1}, {"id": 2}, {"id": 3}]`, string(body))
+
+	_, err = connectors.WithBodyLimit(sql, 30, false).Get(context.Background(), nil, nil, nil)
+	assert.ErrorContains(t, err, "body exceeds max_body_bytes")
+}
@@ -34,6 +34,10 @@ func (c *browserConnector) Get(ctx context.Context, parsedValue builder.Interfac
 		return nil, errEmpty
 	}
 
+	// every browser returns the serialized DOM which is already utf-8
+	setContentType(ctx, decodedContentType)
+	setResponseURL(ctx, formattedURL)
+
 	if c.cfg.Chromium != nil {
 		return getFromChromium(ctx, formattedURL, c.cfg.Chromium, c.logger.With("emulator", "chromium"))
 	}
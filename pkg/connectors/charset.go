@@ -0,0 +1,126 @@
+package connectors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	// decodedContentType marks bodies which are already utf-8 strings (static
+	// values, browser DOM), their meta tags must not trigger transcoding
+	decodedContentType = "text/plain; charset=utf-8"
+)
+
+var (
+	errUnknownCharset = errors.New("unknown charset")
+
+	utf8BOM = []byte{0xef, 0xbb, 0xbf}
+	// xmlDeclaration captures the encoding of the <?xml ... ?> declaration
+	xmlDeclaration = regexp.MustCompile(`^(\s*<\?xml[^>]*?\bencoding\s*=\s*["'])([^"']*)(["'])`)
+	// metaCharset captures <meta charset> and <meta http-equiv content="...; charset=">
+	metaCharset = regexp.MustCompile(`(?i)<meta[^>]+?charset\s*=\s*["']?\s*([\w:.-]+)`)
+)
+
+type charsetConnector struct {
+	original Connector
+	charset  string
+}
+
+func (c *charsetConnector) Get(ctx context.Context, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) ([]byte, error) {
+	meta := responseMetaFromContext(ctx)
+	if meta == nil {
+		ctx, meta = WithResponseMeta(ctx)
+	}
+	body, err := c.original.Get(ctx, parsedValue, index, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return toUTF8(body, meta.contentType, c.charset)
+}
+
+// WithCharset transcodes the body of the original connector to utf-8. The
+// charset is detected from the BOM, the Content-Type header, the xml
+// declaration and <meta charset>, explicit charset overrides the detection
+func WithCharset(original Connector, charset string) Connector {
+	return &charsetConnector{
+		original: original,
+		charset:  charset,
+	}
+}
+
+func toUTF8(body []byte, contentType string, label string) ([]byte, error) {
+	if len(body) == 0 {
+		return body, nil
+	}
+
+	if label == "" {
+		label = detectCharset(body, contentType)
+	}
+
+	encoding, name := charset.Lookup(label)
+	if encoding == nil {
+		return nil, fmt.Errorf("%w: %q", errUnknownCharset, label)
+	}
+	if name == "utf-8" {
+		return body, nil
+	}
+
+	decoded, err := encoding.NewDecoder().Bytes(body)
+	if err != nil {
+		return nil, err
+	}
+	decoded = bytes.TrimPrefix(decoded, utf8BOM)
+
+	// the parser of the xml must not decode the body a second time
+	return xmlDeclaration.ReplaceAll(decoded, []byte("${1}UTF-8${3}")), nil
+}
+
+// detectCharset only trusts declared charsets (BOM, header, xml declaration,
+// meta tag); json is always utf-8, other undeclared bodies are utf-8 unless
+// they are not valid utf-8
+func detectCharset(body []byte, contentType string) string {
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		// json (JSON Feed) is utf-8 by spec whatever the header says
+		return "utf-8"
+	}
+
+	_, name, certain := charset.DetermineEncoding(body, contentType)
+	if certain {
+		return name
+	}
+
+	if label := declaredCharset(body); label != "" {
+		if encoding, declared := charset.Lookup(label); encoding != nil {
+			return declared
+		}
+	}
+	if utf8.Valid(body) {
+		return "utf-8"
+	}
+	return name
+}
+
+// declaredCharset is the encoding of the xml declaration or the meta tag in
+// the first 1024 bytes, like the html prescan
+func declaredCharset(body []byte) string {
+	if match := xmlDeclaration.FindSubmatch(body); match != nil {
+		return string(match[2])
+	}
+
+	head := body
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	if match := metaCharset.FindSubmatch(head); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
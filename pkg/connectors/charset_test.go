@@ -0,0 +1,111 @@
+package connectors_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/connectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// "Привет" in windows-1251
+	cp1251Hello = "\xcf\xf0\xe8\xe2\xe5\xf2"
+	// "日本" in shift_jis
+	sjisJapan = "\x93\xfa\x96\x7b"
+	// "中文" in gbk
+	gbkChinese = "\xd6\xd0\xce\xc4"
+)
+
+func charsetFile(t *testing.T, content string) connectors.Connector {
+	path := filepath.Join(t.TempDir(), "page")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return connectors.NewFile(&config.FileConnectorConfig{Path: path})
+}
+
+func TestCharsetFromHeader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=windows-1251")
+		_, _ = w.Write([]byte("<html><body><p>" + cp1251Hello + "</p></body></html>"))
+	}))
+	defer srv.Close()
+
+	body, err := connectors.WithCharset(connectors.NewAPI(srv.URL, &config.ServerConnectorConfig{
+		Method: http.MethodGet,
+	}, nil), "").Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "<html><body><p>Привет</p></body></html>", string(body))
+}
+
+func TestCharsetUndeclaredUTF8(t *testing.T) {
+	page := "<html><body>" + strings.Repeat("<p>text</p>", 200) + "<p>Café — naïve</p></body></html>"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(page))
+	}))
+	defer srv.Close()
+
+	body, err := connectors.WithCharset(connectors.NewAPI(srv.URL, &config.ServerConnectorConfig{
+		Method: http.MethodGet,
+	}, nil), "").Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, page, string(body))
+}
+
+func TestCharsetDetection(t *testing.T) {
+	for name, tc := range map[string]struct {
+		connector connectors.Connector
+		charset   string
+		expected  string
+	}{
+		"meta charset": {
+			connector: charsetFile(t, `<html><head><meta charset="shift_jis"></head><body>`+sjisJapan+`</body></html>`),
+			expected:  `<html><head><meta charset="shift_jis"></head><body>日本</body></html>`,
+		},
+		"xml declaration": {
+			connector: charsetFile(t, `<?xml version="1.0" encoding="GBK"?><item>`+gbkChinese+`</item>`),
+			expected:  `<?xml version="1.0" encoding="UTF-8"?><item>中文</item>`,
+		},
+		"bom": {
+			connector: charsetFile(t, "\xff\xfe<\x00p\x00>\x00\x42\x04<\x00/\x00p\x00>\x00"),
+			expected:  "<p>т</p>",
+		},
+		"override": {
+			connector: charsetFile(t, `<p>`+cp1251Hello+`</p>`),
+			charset:   "cp1251",
+			expected:  "<p>Привет</p>",
+		},
+		"utf-8 untouched": {
+			connector: charsetFile(t, `<p>Привет</p>`),
+			expected:  "<p>Привет</p>",
+		},
+		"undeclared utf-8 after ascii": {
+			connector: charsetFile(t, "<p>"+strings.Repeat("a", 2048)+"Café — naïve</p>"),
+			expected:  "<p>" + strings.Repeat("a", 2048) + "Café — naïve</p>",
+		},
+		"undeclared legacy": {
+			connector: charsetFile(t, "<p>Caf\xe9</p>"),
+			expected:  "<p>Café</p>",
+		},
+		"static is already decoded": {
+			connector: connectors.NewStatic(&config.StaticConnectorConfig{Value: `<meta charset="windows-1251"><p>Привет</p>`}),
+			expected:  `<meta charset="windows-1251"><p>Привет</p>`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			body, err := connectors.WithCharset(tc.connector, tc.charset).Get(context.Background(), nil, nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(body))
+		})
+	}
+
+	_, err := connectors.WithCharset(charsetFile(t, "<p></p>"), "klingon").Get(context.Background(), nil, nil, nil)
+	assert.ErrorContains(t, err, "unknown charset")
+}
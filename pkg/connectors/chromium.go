@@ -57,10 +57,16 @@ func getFromChromium(ctx context.Context, url string, cfg *config.ChromiumConfig
 
 	cmd := exec.CommandContext(ctxT, cfg.Path, args...)
 
-	var outb, errb bytes.Buffer
-	cmd.Stdout = &outb
+	var errb bytes.Buffer
+	outb := newBodyBuffer(ctx)
+	cmd.Stdout = outb
 	cmd.Stderr = &errb
 	err := cmd.Run()
+	body, errBody := outb.Body()
+	if errBody != nil {
+		logger.Errorw("chromium output is too large", "url", url, "error", errBody.Error())
+		return nil, errBody
+	}
 	if err != nil {
 		logger.Errorw("fatal error during chromium run", "url", url, "error", err.Error())
 		return nil, err
@@ -70,5 +76,5 @@ func getFromChromium(ctx context.Context, url string, cfg *config.ChromiumConfig
 		logger.Errorw("error during chromium running", "url", url, "error", errb.String())
 	}
 
-	return outb.Bytes(), nil
+	return body, nil
 }
@@ -0,0 +1,312 @@
+package connectors
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/oauthflow"
+	"github.com/PxyUp/fitter/pkg/utils"
+	"golang.org/x/net/publicsuffix"
+)
+
+const defaultCookieJarName = "default"
+
+var (
+	cookieJarsMutex sync.Mutex
+	cookieJars      = make(map[string]*sessionJar)
+)
+
+// storageStateCookie is a cookie in the playwright storage state format
+type storageStateCookie struct {
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	Domain   string  `json:"domain"`
+	Path     string  `json:"path"`
+	Expires  float64 `json:"expires"`
+	HttpOnly bool    `json:"httpOnly"`
+	Secure   bool    `json:"secure"`
+	SameSite string  `json:"sameSite"`
+}
+
+type storageState struct {
+	Cookies []*storageStateCookie `json:"cookies"`
+	Origins json.RawMessage       `json:"origins"`
+}
+
+// sessionJar is a http.CookieJar which also remembers the full cookies, so
+// the session can be written back in the playwright storage state format
+type sessionJar struct {
+	jar  *cookiejar.Jar
+	file string
+
+	mutex   sync.Mutex
+	cookies map[string]*storageStateCookie
+	// origins (localStorage) are not used by plain requests, they are kept
+	// untouched so the file stays usable by the playwright connector
+	origins json.RawMessage
+}
+
+// getCookieJar returns the shared jar for the config, loading the import and
+// the persisted files on the first use
+func getCookieJar(cfg *config.CookieJarConfig, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable, log logger.Logger) (*sessionJar, error) {
+	file := ""
+	if cfg.File != "" {
+		file = oauthflow.ExpandPath(utils.Format(cfg.File, parsedValue, index, input))
+	}
+
+	name := utils.Format(cfg.Name, parsedValue, index, input)
+	if name == "" {
+		name = file
+	}
+	if name == "" {
+		name = defaultCookieJarName
+	}
+
+	cookieJarsMutex.Lock()
+	defer cookieJarsMutex.Unlock()
+
+	if jar, ok := cookieJars[name]; ok {
+		return jar, nil
+	}
+
+	jar, err := newSessionJar(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.ImportStorageState != "" {
+		err = jar.load(oauthflow.ExpandPath(utils.Format(cfg.ImportStorageState, parsedValue, index, input)))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if file != "" {
+		err = jar.load(file)
+		if errors.Is(err, os.ErrNotExist) {
+			log.Infow("cookie jar file does not exist yet, starting a fresh session", "path", file)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	cookieJars[name] = jar
+	return jar, nil
+}
+
+func newSessionJar(file string) (*sessionJar, error) {
+	jar, err := cookiejar.New(&cookiejar.Options{
+		PublicSuffixList: publicsuffix.List,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &sessionJar{
+		jar:     jar,
+		file:    file,
+		cookies: make(map[string]*storageStateCookie),
+	}, nil
+}
+
+func (s *sessionJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	s.jar.SetCookies(u, cookies)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, cookie := range cookies {
+		s.track(u, cookie)
+	}
+}
+
+func (s *sessionJar) Cookies(u *url.URL) []*http.Cookie {
+	return s.jar.Cookies(u)
+}
+
+func (s *sessionJar) track(u *url.URL, cookie *http.Cookie) {
+	host := strings.ToLower(u.Hostname())
+
+	// host-only cookies are stored without the leading dot, like playwright does
+	domain := host
+	if cookie.Domain != "" {
+		cookieDomain := strings.TrimPrefix(strings.ToLower(cookie.Domain), ".")
+		if host != cookieDomain && (net.ParseIP(host) != nil || !strings.HasSuffix(host, "."+cookieDomain)) {
+			// rejected by the jar as well
+			return
+		}
+		// the jar keeps the domain of ip hosts as host-only
+		if net.ParseIP(host) == nil {
+			domain = "." + cookieDomain
+		}
+	}
+
+	cookiePath := cookie.Path
+	if cookiePath == "" || cookiePath[0] != '/' {
+		cookiePath = defaultCookiePath(u.Path)
+	}
+
+	// the same (domain, path, name) identity as the jar: a host-only and a
+	// domain cookie of one domain replace each other
+	key := strings.TrimPrefix(domain, ".") + ";" + cookiePath + ";" + cookie.Name
+
+	expires := float64(-1)
+	switch {
+	case cookie.MaxAge < 0:
+		delete(s.cookies, key)
+		return
+	case cookie.MaxAge > 0:
+		expires = float64(time.Now().Add(time.Duration(cookie.MaxAge) * time.Second).Unix())
+	case !cookie.Expires.IsZero():
+		if !cookie.Expires.After(time.Now()) {
+			delete(s.cookies, key)
+			return
+		}
+		expires = float64(cookie.Expires.Unix())
+	}
+
+	s.cookies[key] = &storageStateCookie{
+		Name:     cookie.Name,
+		Value:    cookie.Value,
+		Domain:   domain,
+		Path:     cookiePath,
+		Expires:  expires,
+		HttpOnly: cookie.HttpOnly,
+		Secure:   cookie.Secure,
+		SameSite: sameSiteName(cookie.SameSite),
+	}
+}
+
+// load replays the cookies of a storage state file into the jar
+func (s *sessionJar) load(file string) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	state := &storageState{}
+	err = json.Unmarshal(content, state)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, stored := range state.Cookies {
+		if stored == nil || stored.Name == "" {
+			continue
+		}
+		if stored.Expires > 0 && time.Unix(int64(stored.Expires), 0).Before(now) {
+			continue
+		}
+
+		host := strings.TrimPrefix(stored.Domain, ".")
+		scheme := "http"
+		if stored.Secure {
+			scheme = "https"
+		}
+
+		cookie := &http.Cookie{
+			Name:     stored.Name,
+			Value:    stored.Value,
+			Path:     stored.Path,
+			Secure:   stored.Secure,
+			HttpOnly: stored.HttpOnly,
+			SameSite: sameSiteMode(stored.SameSite),
+		}
+		if strings.HasPrefix(stored.Domain, ".") {
+			cookie.Domain = host
+		}
+		if stored.Expires > 0 {
+			cookie.Expires = time.Unix(int64(stored.Expires), 0)
+		}
+
+		s.SetCookies(&url.URL{Scheme: scheme, Host: host, Path: stored.Path}, []*http.Cookie{cookie})
+	}
+
+	if len(state.Origins) > 0 {
+		s.mutex.Lock()
+		s.origins = state.Origins
+		s.mutex.Unlock()
+	}
+
+	return nil
+}
+
+// persist writes the jar to its file, if any, dropping expired cookies
+func (s *sessionJar) persist() error {
+	if s.file == "" {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	keys := make([]string, 0, len(s.cookies))
+	for key := range s.cookies {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	now := float64(time.Now().Unix())
+	state := &storageState{
+		Cookies: make([]*storageStateCookie, 0, len(keys)),
+		Origins: s.origins,
+	}
+	for _, key := range keys {
+		cookie := s.cookies[key]
+		if cookie.Expires > 0 && cookie.Expires <= now {
+			delete(s.cookies, key)
+			continue
+		}
+		state.Cookies = append(state.Cookies, cookie)
+	}
+	if len(state.Origins) == 0 {
+		state.Origins = json.RawMessage("[]")
+	}
+
+	return oauthflow.SaveJSONFile(s.file, state)
+}
+
+// defaultCookiePath is the directory of the request path (RFC 6265 5.1.4)
+func defaultCookiePath(requestPath string) string {
+	if requestPath == "" || requestPath[0] != '/' {
+		return "/"
+	}
+	return path.Dir(requestPath)
+}
+
+func sameSiteName(mode http.SameSite) string {
+	switch mode {
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteNoneMode:
+		return "None"
+	default:
+		return "Lax"
+	}
+}
+
+func sameSiteMode(name string) http.SameSite {
+	switch strings.ToLower(name) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	case "lax":
+		return http.SameSiteLaxMode
+	default:
+		return http.SameSiteDefaultMode
+	}
+}
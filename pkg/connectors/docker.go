@@ -186,9 +186,15 @@ func getFromDocker(ctx context.Context, url string, cfg *config.DockerConfig, lo
 		return nil, err
 	}
 
-	var outb, errb bytes.Buffer
-
-	_, err = stdcopy.StdCopy(&outb, &errb, data)
+	var errb bytes.Buffer
+	outb := newBodyBuffer(ctx)
+
+	_, err = stdcopy.StdCopy(outb, &errb, data)
+	body, errBody := outb.Body()
+	if errBody != nil {
+		logger.Errorw("docker container output is too large", "url", url, "error", errBody.Error())
+		return nil, errBody
+	}
 	if err != nil {
 		logger.Errorw("unable to copy logs from docker container", "error", err.Error())
 		return nil, err
@@ -198,5 +204,5 @@ func getFromDocker(ctx context.Context, url string, cfg *config.DockerConfig, lo
 		logger.Errorw("error during docker running", "url", url, "error", errb.String())
 	}
 
-	return outb.Bytes(), nil
+	return body, nil
 }
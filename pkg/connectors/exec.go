@@ -0,0 +1,130 @@
+package connectors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/limitter"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/utils"
+)
+
+const (
+	// waitDelay bounds the wait for the output pipes after the process is killed
+	waitDelay = 5 * time.Second
+)
+
+var (
+	errEmptyCommand = errors.New("empty command")
+	errExecFailed   = errors.New("command failed")
+)
+
+type execConnector struct {
+	cfg    *config.ExecConnectorConfig
+	logger logger.Logger
+}
+
+func NewExec(cfg *config.ExecConnectorConfig) *execConnector {
+	return &execConnector{
+		cfg:    cfg,
+		logger: logger.Null,
+	}
+}
+
+func (e *execConnector) WithLogger(logger logger.Logger) *execConnector {
+	e.logger = logger
+	return e
+}
+
+func (e *execConnector) Get(ctx context.Context, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) ([]byte, error) {
+	command := utils.Format(e.cfg.Command, parsedValue, index, input)
+	if command == "" {
+		return nil, errEmptyCommand
+	}
+
+	// every arg stays a separate argv entry, placeholders can't inject arguments
+	args := make([]string, len(e.cfg.Args))
+	for i, arg := range e.cfg.Args {
+		args[i] = utils.Format(arg, parsedValue, index, input)
+	}
+
+	if processLimit := limitter.ProcessLimiter(); processLimit != nil {
+		errLimit := processLimit.Acquire(ctx, 1)
+		if errLimit != nil {
+			e.logger.Errorw("unable to acquire process limit semaphore", "command", command, "error", errLimit.Error())
+			return nil, errLimit
+		}
+		defer processLimit.Release(1)
+	}
+
+	t := timeout
+	if e.cfg.Timeout > 0 {
+		t = time.Duration(e.cfg.Timeout) * time.Second
+	}
+	ctxT, cancel := context.WithTimeout(ctx, t)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctxT, command, args...)
+	cmd.Dir = utils.Format(e.cfg.Dir, parsedValue, index, input)
+	cmd.Env = e.env(parsedValue, index, input)
+	cmd.Stdin = strings.NewReader(utils.Format(e.cfg.Stdin, parsedValue, index, input))
+	cmd.WaitDelay = waitDelay
+	killProcessGroup(cmd)
+
+	var stderr bytes.Buffer
+	stdout := newBodyBuffer(ctx)
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+
+	e.logger.Infow("running command", "command", command, "args", strings.Join(args, " "))
+	err := cmd.Run()
+	if errCtx := ctxT.Err(); errCtx != nil {
+		e.logger.Errorw("command cancelled", "command", command, "error", errCtx.Error())
+		return nil, errCtx
+	}
+
+	body, errBody := stdout.Body()
+	if errBody != nil {
+		e.logger.Errorw("command output is too large", "command", command, "error", errBody.Error())
+		return nil, errBody
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		err = fmt.Errorf("%w: exit code %d: %s", errExecFailed, exitErr.ExitCode(), strings.TrimSpace(stderr.String()))
+	}
+	if err != nil {
+		e.logger.Errorw("unable to run command", "command", command, "error", err.Error())
+		return nil, err
+	}
+
+	if stderr.Len() > 0 {
+		e.logger.Debugw("command stderr", "command", command, "stderr", stderr.String())
+	}
+
+	return body, nil
+}
+
+func (e *execConnector) env(parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) []string {
+	env := os.Environ()
+
+	keys := make([]string, 0, len(e.cfg.Env))
+	for k := range e.cfg.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		env = append(env, utils.Format(k, parsedValue, index, input)+"="+utils.Format(e.cfg.Env[k], parsedValue, index, input))
+	}
+	return env
+}
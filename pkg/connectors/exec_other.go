@@ -0,0 +1,11 @@
+//go:build !unix
+
+package connectors
+
+import (
+	"os/exec"
+)
+
+// killProcessGroup keeps the default cancel, which kills the process only
+func killProcessGroup(_ *exec.Cmd) {
+}
@@ -0,0 +1,77 @@
+//go:build unix
+
+package connectors_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/connectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecConnectorStdout(t *testing.T) {
+	body, err := connectors.NewExec(&config.ExecConnectorConfig{
+		Command: "sh",
+		Args:    []string{"-c", `printf '{"arg": "%s", "env": "%s", "stdin": "%s"}' "$1" "$FITTER_TEST" "$(cat)"`, "sh", "{PL}; rm -rf /"},
+		Env:     map[string]string{"FITTER_TEST": "env-{INDEX}"},
+		Stdin:   "input-{PL}",
+	}).Get(context.Background(), builder.PureString("value"), &[]uint32{7}[0], nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"arg": "value; rm -rf /", "env": "env-7", "stdin": "input-value"}`, string(body))
+}
+
+func TestExecConnectorExitCode(t *testing.T) {
+	_, err := connectors.NewExec(&config.ExecConnectorConfig{
+		Command: "sh",
+		Args:    []string{"-c", "echo partial; echo 'resource not found' >&2; exit 3"},
+	}).Get(context.Background(), nil, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exit code 3")
+	assert.Contains(t, err.Error(), "resource not found")
+}
+
+func TestExecConnectorTimeoutKillsProcessTree(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+
+	start := time.Now()
+	_, err := connectors.NewExec(&config.ExecConnectorConfig{
+		Command: "sh",
+		Args:    []string{"-c", "sleep 30 & echo $! > " + pidFile + "; wait"},
+		Timeout: 1,
+	}).Get(context.Background(), nil, nil, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	content, err := os.ReadFile(pidFile)
+	require.NoError(t, err)
+	var pid int
+	_, err = fmt.Sscan(string(content), &pid)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return syscall.Kill(pid, 0) != nil
+	}, 2*time.Second, 50*time.Millisecond, "child process survived the timeout")
+}
+
+func TestExecConnectorMaxBodyBytes(t *testing.T) {
+	exec := connectors.NewExec(&config.ExecConnectorConfig{
+		Command: "sh",
+		Args:    []string{"-c", "yes | head -c 100000"},
+	})
+
+	_, err := connectors.WithBodyLimit(exec, 10, false).Get(context.Background(), nil, nil, nil)
+	assert.ErrorContains(t, err, "body exceeds max_body_bytes")
+
+	body, err := connectors.WithBodyLimit(exec, 10, true).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "y\ny\ny\ny\ny\n", string(body))
+}
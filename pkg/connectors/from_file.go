@@ -6,8 +6,11 @@ import (
 	"github.com/PxyUp/fitter/pkg/config"
 	"github.com/PxyUp/fitter/pkg/logger"
 	"github.com/PxyUp/fitter/pkg/utils"
-	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
 type fileConnector struct {
@@ -15,7 +18,7 @@ type fileConnector struct {
 	logger logger.Logger
 }
 
-func (j *fileConnector) Get(_ context.Context, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) ([]byte, error) {
+func (j *fileConnector) Get(ctx context.Context, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) ([]byte, error) {
 	file, err := os.Open(utils.Format(j.cfg.Path, parsedValue, index, input))
 	if err != nil {
 		j.logger.Errorw("cant open file", "error", err.Error())
@@ -25,7 +28,7 @@ func (j *fileConnector) Get(_ context.Context, parsedValue builder.Interfacable,
 		_ = file.Close()
 	}()
 
-	body, err := io.ReadAll(file)
+	body, err := readBody(ctx, file)
 	if err != nil {
 		j.logger.Errorw("cant read file content", "error", err.Error())
 		return nil, err
@@ -49,3 +52,70 @@ func (j *fileConnector) WithLogger(logger logger.Logger) *fileConnector {
 	j.logger = logger
 	return j
 }
+
+// FileMatch is a file found by MatchFiles
+type FileMatch struct {
+	Path    string
+	Name    string
+	ModTime time.Time
+}
+
+// MatchFiles lists the files of the Path directory matching the Glob pattern
+// in lexical walk order. Patterns without separator are matched against the file
+// name, other ones against the path relative to the directory
+func MatchFiles(cfg *config.FileConnectorConfig, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) ([]*FileMatch, error) {
+	root := utils.Format(cfg.Path, parsedValue, index, input)
+	if root == "" {
+		root = "."
+	}
+	pattern := utils.Format(cfg.Glob, parsedValue, index, input)
+	// validate the pattern once, filepath.Match reports it only on a candidate
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	byName := !strings.ContainsRune(pattern, '/') && !strings.ContainsRune(pattern, filepath.Separator)
+
+	var matches []*FileMatch
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != root && !cfg.Recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		candidate := entry.Name()
+		if !byName {
+			rel, errRel := filepath.Rel(root, path)
+			if errRel != nil {
+				return errRel
+			}
+			candidate = filepath.ToSlash(rel)
+		}
+		if ok, _ := filepath.Match(pattern, candidate); !ok {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		matches = append(matches, &FileMatch{
+			Path:    path,
+			Name:    entry.Name(),
+			ModTime: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
@@ -0,0 +1,184 @@
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/utils"
+	"github.com/tidwall/gjson"
+)
+
+const defaultCursorVariable = "after"
+
+var (
+	errGraphQL       = errors.New("graphql error")
+	errGraphQLNoData = errors.New("graphql response without data")
+)
+
+type graphQLConnector struct {
+	url       string
+	cfg       *config.GraphQLConnectorConfig
+	serverCfg *config.ServerConnectorConfig
+	logger    logger.Logger
+}
+
+type graphQLRequest struct {
+	Query         string          `json:"query"`
+	OperationName string          `json:"operationName,omitempty"`
+	Variables     json.RawMessage `json:"variables,omitempty"`
+}
+
+func NewGraphQL(url string, cfg *config.GraphQLConnectorConfig) *graphQLConnector {
+	serverCfg := &config.ServerConnectorConfig{}
+	if cfg.Server != nil {
+		copied := *cfg.Server
+		serverCfg = &copied
+	}
+	serverCfg.Method = http.MethodPost
+	serverCfg.Body = ""
+	serverCfg.JsonRawBody = nil
+	serverCfg.Form = nil
+	serverCfg.Multipart = nil
+
+	headers := make(map[string]string, len(serverCfg.Headers)+1)
+	for k, v := range serverCfg.Headers {
+		headers[k] = v
+	}
+	if _, ok := headers["Accept"]; !ok {
+		headers["Accept"] = jsonContentType
+	}
+	serverCfg.Headers = headers
+
+	return &graphQLConnector{
+		url:       url,
+		cfg:       cfg,
+		serverCfg: serverCfg,
+		logger:    logger.Null,
+	}
+}
+
+func (g *graphQLConnector) WithLogger(logger logger.Logger) *graphQLConnector {
+	g.logger = logger
+	return g
+}
+
+func (g *graphQLConnector) Get(ctx context.Context, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) ([]byte, error) {
+	variables := make(map[string]builder.Interfacable, len(g.cfg.Variables))
+	for name, variable := range g.cfg.Variables {
+		if variable == nil {
+			continue
+		}
+		value := variable.Value
+		if len(variable.Raw) > 0 {
+			value = string(variable.Raw)
+		}
+		variables[name] = builder.Static(&builder.StaticCfg{
+			Type:  variable.Type,
+			Value: utils.Format(value, parsedValue, index, input),
+		})
+	}
+
+	if g.cfg.Pagination == nil {
+		data, err := g.query(ctx, variables, parsedValue, index, input)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(data.Raw), nil
+	}
+
+	return g.paginate(ctx, variables, parsedValue, index, input)
+}
+
+// paginate follows pageInfo.endCursor while pageInfo.hasNextPage is true and
+// collects the nodes of every page into one json array
+func (g *graphQLConnector) paginate(ctx context.Context, variables map[string]builder.Interfacable, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) ([]byte, error) {
+	pagination := g.cfg.Pagination
+	cursorVariable := pagination.CursorVariable
+	if cursorVariable == "" {
+		cursorVariable = defaultCursorVariable
+	}
+
+	var nodes []string
+	lastCursor := ""
+	for page := uint32(0); pagination.MaxPages == 0 || page < pagination.MaxPages; page++ {
+		data, err := g.query(ctx, variables, parsedValue, index, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, node := range data.Get(pagination.NodesPath).Array() {
+			nodes = append(nodes, node.Raw)
+		}
+
+		pageInfo := data.Get(pagination.PageInfoPath)
+		if !pageInfo.Get("hasNextPage").Bool() {
+			break
+		}
+
+		cursor := pageInfo.Get("endCursor")
+		if cursor.Type != gjson.String || cursor.String() == "" || cursor.String() == lastCursor {
+			g.logger.Infow("stop pagination, no new cursor", "url", g.url, "page", fmt.Sprintf("%d", page))
+			break
+		}
+		lastCursor = cursor.String()
+		variables[cursorVariable] = builder.String(lastCursor, false)
+	}
+
+	return []byte("[" + strings.Join(nodes, ",") + "]"), nil
+}
+
+func (g *graphQLConnector) query(ctx context.Context, variables map[string]builder.Interfacable, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) (gjson.Result, error) {
+	request := &graphQLRequest{
+		Query:         g.cfg.Query,
+		OperationName: g.cfg.OperationName,
+	}
+	if len(variables) > 0 {
+		request.Variables = json.RawMessage(builder.Object(variables).ToJson())
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		g.logger.Errorw("unable to marshal graphql request", "error", err.Error())
+		return gjson.Result{}, err
+	}
+
+	api := &apiConnector{
+		url:    g.url,
+		cfg:    g.serverCfg,
+		logger: g.logger,
+		body:   body,
+	}
+	_, resp, err := api.get(ctx, parsedValue, index, input)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+
+	response := gjson.ParseBytes(resp)
+	if errs := response.Get("errors").Array(); len(errs) > 0 {
+		messages := make([]string, len(errs))
+		for i, e := range errs {
+			messages[i] = e.Get("message").String()
+			if messages[i] == "" {
+				messages[i] = e.Raw
+			}
+		}
+		err = fmt.Errorf("%w: %s", errGraphQL, strings.Join(messages, "; "))
+		g.logger.Errorw("graphql request returned errors", "url", g.url, "error", err.Error())
+		return gjson.Result{}, err
+	}
+
+	data := response.Get("data")
+	if !data.Exists() || data.Type == gjson.Null {
+		g.logger.Errorw("graphql response without data", "url", g.url, "body", string(resp))
+		return gjson.Result{}, errGraphQLNoData
+	}
+
+	return data, nil
+}
@@ -0,0 +1,137 @@
+package connectors_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/connectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type graphQLTestRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func TestGraphQLConnectorVariables(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		req := &graphQLTestRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		assert.Equal(t, "query Repo($name: String!, $first: Int!) { repo(name: $name) { stars } }", req.Query)
+		assert.Equal(t, "Repo", req.OperationName)
+		assert.Equal(t, map[string]interface{}{"name": "fitter", "first": float64(3), "tags": []interface{}{"go"}}, req.Variables)
+
+		_, _ = w.Write([]byte(`{"data": {"repo": {"stars": 42}}}`))
+	}))
+	defer srv.Close()
+
+	body, err := connectors.NewGraphQL(srv.URL, &config.GraphQLConnectorConfig{
+		Query:         "query Repo($name: String!, $first: Int!) { repo(name: $name) { stars } }",
+		OperationName: "Repo",
+		Variables: map[string]*config.StaticGeneratedFieldConfig{
+			"name":  {Type: config.String, Value: "{PL}"},
+			"first": {Type: config.Int, Value: "{INDEX}"},
+			"tags":  {Type: config.Array, Raw: json.RawMessage(`["go"]`)},
+		},
+		Server: &config.ServerConnectorConfig{
+			Headers: map[string]string{"Authorization": "Bearer token"},
+		},
+	}).Get(context.Background(), builder.PureString("fitter"), &[]uint32{3}[0], nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"repo": {"stars": 42}}`, string(body))
+}
+
+func TestGraphQLConnectorErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": null, "errors": [{"message": "field 'x' not found"}, {"message": "rate limited"}]}`))
+	}))
+	defer srv.Close()
+
+	_, err := connectors.NewGraphQL(srv.URL, &config.GraphQLConnectorConfig{
+		Query: "{ x }",
+	}).Get(context.Background(), nil, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "field 'x' not found; rate limited")
+}
+
+func TestGraphQLConnectorPagination(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"data": {"search": {"nodes": [{"id": 1}, {"id": 2}], "pageInfo": {"hasNextPage": true, "endCursor": "c1"}}}}`,
+		"c1": `{"data": {"search": {"nodes": [{"id": 3}], "pageInfo": {"hasNextPage": true, "endCursor": "c2"}}}}`,
+		"c2": `{"data": {"search": {"nodes": [{"id": 4}], "pageInfo": {"hasNextPage": false, "endCursor": null}}}}`,
+	}
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		req := &graphQLTestRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		cursor, _ := req.Variables["cursor"].(string)
+		assert.Equal(t, "go", req.Variables["q"])
+		_, _ = w.Write([]byte(pages[cursor]))
+	}))
+	defer srv.Close()
+
+	cfg := &config.GraphQLConnectorConfig{
+		Query: "query($q: String!, $cursor: String) { search(q: $q, after: $cursor) { nodes { id } pageInfo { hasNextPage endCursor } } }",
+		Variables: map[string]*config.StaticGeneratedFieldConfig{
+			"q": {Type: config.String, Value: "go"},
+		},
+		Pagination: &config.GraphQLPaginationConfig{
+			PageInfoPath:   "search.pageInfo",
+			NodesPath:      "search.nodes",
+			CursorVariable: "cursor",
+		},
+	}
+
+	body, err := connectors.NewGraphQL(srv.URL, cfg).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"id": 1}, {"id": 2}, {"id": 3}, {"id": 4}]`, string(body))
+	assert.Equal(t, 3, requests)
+
+	cfg.Pagination.MaxPages = 2
+	body, err = connectors.NewGraphQL(srv.URL, cfg).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"id": 1}, {"id": 2}, {"id": 3}]`, string(body))
+}
+
+func TestGraphQLConnectorPaginationEdges(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"data": {"search": {"edges": [{"cursor": "c0", "node": {"id": 1}}, {"cursor": "c1", "node": {"id": 2}}], "pageInfo": {"hasNextPage": true, "endCursor": "c1"}}}}`,
+		"c1": `{"data": {"search": {"edges": [{"cursor": "c2", "node": {"id": 3}}], "pageInfo": {"hasNextPage": false, "endCursor": "c2"}}}}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &graphQLTestRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		cursor, _ := req.Variables["after"].(string)
+		_, _ = w.Write([]byte(pages[cursor]))
+	}))
+	defer srv.Close()
+
+	for nodesPath, expected := range map[string]string{
+		"search.edges.#.node": `[{"id": 1}, {"id": 2}, {"id": 3}]`,
+		"search.edges":        `[{"cursor": "c0", "node": {"id": 1}}, {"cursor": "c1", "node": {"id": 2}}, {"cursor": "c2", "node": {"id": 3}}]`,
+	} {
+		t.Run(nodesPath, func(t *testing.T) {
+			body, err := connectors.NewGraphQL(srv.URL, &config.GraphQLConnectorConfig{
+				Query: "query($after: String) { search(after: $after) { edges { cursor node { id } } pageInfo { hasNextPage endCursor } } }",
+				Pagination: &config.GraphQLPaginationConfig{
+					PageInfoPath: "search.pageInfo",
+					NodesPath:    nodesPath,
+				},
+			}).Get(context.Background(), nil, nil, nil)
+			require.NoError(t, err)
+			assert.JSONEq(t, expected, string(body))
+		})
+	}
+}
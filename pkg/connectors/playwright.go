@@ -255,7 +255,10 @@ func getFromPlaywright(ctx context.Context, url string, cfg *config.PlaywrightCo
 
 	select {
 	case <-res:
-		return []byte(content), err
+		if err != nil {
+			return nil, err
+		}
+		return bodyLimitFromContext(ctx).apply([]byte(content))
 	case <-ctxT.Done():
 		return nil, ctxT.Err()
 	}
@@ -0,0 +1,41 @@
+package connectors
+
+import "context"
+
+type responseMetaKey struct{}
+
+// ResponseMeta is filled by the connectors which know the content type of the
+// body or the final url of the response (after redirects)
+type ResponseMeta struct {
+	contentType string
+	url         string
+}
+
+// URL of the response, empty when the connector doesn't know it (static, file...)
+func (m *ResponseMeta) URL() string {
+	return m.url
+}
+
+// WithResponseMeta attaches a fresh meta to the context, the connectors
+// called with the returned context fill it up
+func WithResponseMeta(ctx context.Context) (context.Context, *ResponseMeta) {
+	meta := &ResponseMeta{}
+	return context.WithValue(ctx, responseMetaKey{}, meta), meta
+}
+
+func responseMetaFromContext(ctx context.Context) *ResponseMeta {
+	meta, _ := ctx.Value(responseMetaKey{}).(*ResponseMeta)
+	return meta
+}
+
+func setContentType(ctx context.Context, contentType string) {
+	if meta := responseMetaFromContext(ctx); meta != nil {
+		meta.contentType = contentType
+	}
+}
+
+func setResponseURL(ctx context.Context, url string) {
+	if meta := responseMetaFromContext(ctx); meta != nil {
+		meta.url = url
+	}
+}
@@ -28,6 +28,8 @@ type apiConnector struct {
 	logger logger.Logger
 	client *http.Client
 	cfg    *config.ServerConnectorConfig
+	// body is sent instead of the configured body when set, without formatting
+	body []byte
 }
 
 var (
@@ -64,18 +66,19 @@ func (api *apiConnector) GetWithHeaders(ctx context.Context, parsedValue builder
 }
 
 func (api *apiConnector) get(ctx context.Context, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) (http.Header, []byte, error) {
-	formattedBody := utils.Format(api.cfg.Body, parsedValue, index, input)
-	if api.cfg.JsonRawBody != nil && len(api.cfg.JsonRawBody) > 0 {
-		formattedBody = utils.Format(string(api.cfg.JsonRawBody), parsedValue, index, input)
-	}
-
 	formattedURL := utils.Format(api.url, parsedValue, index, input)
 
 	if formattedURL == "" {
 		return nil, nil, errEmpty
 	}
 
-	err := sem.Acquire(ctx, 1)
+	formattedBody, contentType, err := api.requestBody(parsedValue, index, input)
+	if err != nil {
+		api.logger.Errorw("unable to build request body", "url", formattedURL, "error", err.Error())
+		return nil, nil, err
+	}
+
+	err = sem.Acquire(ctx, 1)
 	if err != nil {
 		api.logger.Errorw("unable to acquire semaphore", "method", api.cfg.Method, "url", formattedURL, "error", err.Error())
 		return nil, nil, err
@@ -83,15 +86,24 @@ func (api *apiConnector) get(ctx context.Context, parsedValue builder.Interfacab
 
 	defer sem.Release(1)
 
-	req, err := http.NewRequest(api.cfg.Method, formattedURL, bytes.NewBufferString(formattedBody))
+	req, err := http.NewRequest(api.cfg.Method, formattedURL, bytes.NewReader(formattedBody))
 
 	if err != nil {
 		api.logger.Errorw("unable to create http request", "error", err.Error())
 		return nil, nil, err
 	}
 
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
 	for k, v := range api.cfg.Headers {
-		req.Header.Add(utils.Format(k, parsedValue, index, input), utils.Format(v, parsedValue, index, input))
+		formattedKey := utils.Format(k, parsedValue, index, input)
+		if contentType != "" && http.CanonicalHeaderKey(formattedKey) == "Content-Type" {
+			// the boundary of multipart bodies must match the generated one
+			continue
+		}
+		req.Header.Add(formattedKey, utils.Format(v, parsedValue, index, input))
 	}
 
 	client := http_client.GetDefaultClient()
@@ -99,22 +111,26 @@ func (api *apiConnector) get(ctx context.Context, parsedValue builder.Interfacab
 		client = api.client
 	}
 
-	if api.cfg.Proxy != nil {
-		proxyUrl, errProxy := url.Parse(utils.Format(api.cfg.Proxy.Server, parsedValue, index, input))
-		if errProxy != nil {
-			api.logger.Errorw("unable to create proxy", "error", errProxy.Error())
-			return nil, nil, err
+	if api.cfg.Proxy != nil || api.cfg.Transport != nil {
+		sharedClient, errClient := api.sharedClient(parsedValue, index, input)
+		if errClient != nil {
+			api.logger.Errorw("unable to create http client", "error", errClient.Error())
+			return nil, nil, errClient
 		}
+		client = sharedClient
+	}
 
-		if api.cfg.Proxy.Username != "" {
-			if api.cfg.Proxy.Password != "" {
-				proxyUrl.User = url.UserPassword(utils.Format(api.cfg.Proxy.Username, parsedValue, index, input), utils.Format(api.cfg.Proxy.Password, parsedValue, index, input))
-			} else {
-				proxyUrl.User = url.User(utils.Format(api.cfg.Proxy.Username, parsedValue, index, input))
-			}
+	var jar *sessionJar
+	if api.cfg.CookieJar != nil {
+		jar, err = getCookieJar(api.cfg.CookieJar, parsedValue, index, input, api.logger)
+		if err != nil {
+			api.logger.Errorw("unable to load cookie jar", "error", err.Error())
+			return nil, nil, err
 		}
-		api.logger.Debugw("set proxy", "server", api.cfg.Proxy.Server, "username", api.cfg.Proxy.Username, "password", api.cfg.Proxy.Password)
-		client.Transport = &http.Transport{Proxy: http.ProxyURL(proxyUrl)}
+		// the pooled client is shared, the copy only differs by its jar
+		jarClient := *client
+		jarClient.Jar = jar
+		client = &jarClient
 	}
 
 	if hostLimit := limitter.HostLimiter(req.Host); hostLimit != nil {
@@ -156,7 +172,11 @@ func (api *apiConnector) get(ctx context.Context, parsedValue builder.Interfacab
 		return client.Do(attemptReq)
 	}
 
-	api.logger.Infow("sending request to url", "url", formattedURL, "body", formattedBody)
+	if len(api.cfg.Multipart) > 0 {
+		api.logger.Infow("sending request to url", "url", formattedURL, "content_type", contentType, "body_size", strconv.Itoa(len(formattedBody)))
+	} else {
+		api.logger.Infow("sending request to url", "url", formattedURL, "body", string(formattedBody))
+	}
 	resp, err := doRequest()
 	if err == nil && api.cfg.OAuth2 != nil && resp.StatusCode == http.StatusUnauthorized {
 		// cached token may be revoked: drop it and retry once with a fresh one
@@ -182,16 +202,56 @@ func (api *apiConnector) get(ctx context.Context, parsedValue builder.Interfacab
 		defer resp.Body.Close()
 	}
 
-	bytes, err := io.ReadAll(resp.Body)
+	if jar != nil {
+		// keep the session alive between runs; non-fatal
+		if errPersist := jar.persist(); errPersist != nil {
+			api.logger.Errorw("unable to persist cookie jar", "path", jar.file, "error", errPersist.Error())
+		}
+	}
+
+	if limit := bodyLimitFromContext(ctx); limit.maxBytes > 0 && !limit.truncate && resp.ContentLength > limit.maxBytes {
+		err = limit.exceeded()
+		api.logger.Errorw("http response is too large", "url", formattedURL, "content_length", strconv.FormatInt(resp.ContentLength, 10), "error", err.Error())
+		return nil, nil, err
+	}
+
+	bytes, err := readBody(ctx, resp.Body)
 	if err != nil {
 		api.logger.Errorw("unable to read http response", "error", err.Error())
 		return nil, nil, err
 	}
 
+	setContentType(ctx, resp.Header.Get("Content-Type"))
+	if resp.Request != nil && resp.Request.URL != nil {
+		setResponseURL(ctx, resp.Request.URL.String())
+	}
 	api.logger.Debugw("returned response", "status_code", resp.Status, "body", string(bytes))
 	return resp.Header, bytes, nil
 }
 
+// sharedClient returns the pooled client for the proxy and transport settings
+func (api *apiConnector) sharedClient(parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) (*http.Client, error) {
+	var proxyUrl *url.URL
+	if api.cfg.Proxy != nil {
+		parsedProxy, errProxy := url.Parse(utils.Format(api.cfg.Proxy.Server, parsedValue, index, input))
+		if errProxy != nil {
+			return nil, errProxy
+		}
+
+		if api.cfg.Proxy.Username != "" {
+			if api.cfg.Proxy.Password != "" {
+				parsedProxy.User = url.UserPassword(utils.Format(api.cfg.Proxy.Username, parsedValue, index, input), utils.Format(api.cfg.Proxy.Password, parsedValue, index, input))
+			} else {
+				parsedProxy.User = url.User(utils.Format(api.cfg.Proxy.Username, parsedValue, index, input))
+			}
+		}
+		api.logger.Debugw("set proxy", "server", api.cfg.Proxy.Server, "username", api.cfg.Proxy.Username, "password", api.cfg.Proxy.Password)
+		proxyUrl = parsedProxy
+	}
+
+	return http_client.GetClient(proxyUrl, api.cfg.Transport)
+}
+
 func (api *apiConnector) Get(ctx context.Context, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) ([]byte, error) {
 	_, body, err := api.get(ctx, parsedValue, index, input)
 	return body, err
@@ -0,0 +1,106 @@
+package connectors
+
+import (
+	"bytes"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/utils"
+)
+
+const (
+	formContentType = "application/x-www-form-urlencoded"
+	jsonContentType = "application/json"
+)
+
+// requestBody returns the formatted body of the request and its content type
+// (empty for raw bodies, where the user controls the headers); prebuilt
+// bodies are always json
+func (api *apiConnector) requestBody(parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) ([]byte, string, error) {
+	if api.body != nil {
+		return api.body, jsonContentType, nil
+	}
+
+	if len(api.cfg.Multipart) > 0 {
+		return api.multipartBody(parsedValue, index, input)
+	}
+
+	if len(api.cfg.Form) > 0 {
+		values := url.Values{}
+		for k, v := range api.cfg.Form {
+			values.Add(utils.Format(k, parsedValue, index, input), utils.Format(v, parsedValue, index, input))
+		}
+		return []byte(values.Encode()), formContentType, nil
+	}
+
+	if len(api.cfg.JsonRawBody) > 0 {
+		return []byte(utils.Format(string(api.cfg.JsonRawBody), parsedValue, index, input)), "", nil
+	}
+
+	return []byte(utils.Format(api.cfg.Body, parsedValue, index, input)), "", nil
+}
+
+func (api *apiConnector) multipartBody(parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) ([]byte, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for _, part := range api.cfg.Multipart {
+		if part == nil {
+			continue
+		}
+
+		name := utils.Format(part.Name, parsedValue, index, input)
+		if part.File == "" {
+			err := writer.WriteField(name, utils.Format(part.Value, parsedValue, index, input))
+			if err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+
+		filePath := utils.Format(part.File, parsedValue, index, input)
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, "", err
+		}
+
+		fileName := utils.Format(part.FileName, parsedValue, index, input)
+		if fileName == "" {
+			fileName = filepath.Base(filePath)
+		}
+
+		contentType := utils.Format(part.ContentType, parsedValue, index, input)
+		if contentType == "" {
+			contentType = mime.TypeByExtension(filepath.Ext(fileName))
+		}
+		if contentType == "" {
+			contentType = http.DetectContentType(content)
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", multipart.FileContentDisposition(name, fileName))
+		header.Set("Content-Type", contentType)
+
+		partWriter, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", err
+		}
+		_, err = partWriter.Write(content)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	err := writer.Close()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return body.Bytes(), writer.FormDataContentType(), nil
+}
@@ -2,14 +2,19 @@ package connectors_test
 
 import (
 	"context"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/PxyUp/fitter/pkg/builder"
 	"github.com/PxyUp/fitter/pkg/config"
 	"github.com/PxyUp/fitter/pkg/connectors"
 	"github.com/PxyUp/fitter/pkg/oauthflow"
@@ -257,3 +262,240 @@ func TestApiConnectorOAuth2TokenFileRotationPersisted(t *testing.T) {
 	assert.Equal(t, "file-refresh-2", persisted.RefreshToken, "rotated refresh token should be written back")
 	assert.Equal(t, "acc-2", persisted.AccessToken)
 }
+
+func TestApiConnectorTransportTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"tls":true}`))
+	}))
+	defer srv.Close()
+
+	_, err := connectors.NewAPI(srv.URL, &config.ServerConnectorConfig{Method: http.MethodGet}, nil).Get(context.Background(), nil, nil, nil)
+	assert.Error(t, err, "self-signed certificate must not be trusted by default")
+
+	body, err := connectors.NewAPI(srv.URL, &config.ServerConnectorConfig{
+		Method:    http.MethodGet,
+		Transport: &config.TransportConfig{InsecureSkipVerify: true},
+	}, nil).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"tls":true}`, string(body))
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0600))
+
+	body, err = connectors.NewAPI(srv.URL, &config.ServerConnectorConfig{
+		Method:    http.MethodGet,
+		Transport: &config.TransportConfig{CAFile: caFile, DisableHTTP2: true},
+	}, nil).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"tls":true}`, string(body))
+}
+
+func TestApiConnectorTransportRedirect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/start" {
+			http.Redirect(w, r, "/target", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte(`target`))
+	}))
+	defer srv.Close()
+
+	body, err := connectors.NewAPI(srv.URL+"/start", &config.ServerConnectorConfig{Method: http.MethodGet}, nil).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `target`, string(body))
+
+	body, err = connectors.NewAPI(srv.URL+"/start", &config.ServerConnectorConfig{
+		Method:    http.MethodGet,
+		Transport: &config.TransportConfig{Redirect: config.RedirectNone},
+	}, nil).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "/target")
+}
+
+func TestApiConnectorCookieJarLoginThenFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/", HttpOnly: true, MaxAge: 3600})
+			_, _ = w.Write([]byte(`{"logged":true}`))
+		case "/me":
+			cookie, err := r.Cookie("session")
+			if err != nil || cookie.Value != "abc" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"user":"john"}`))
+		}
+	}))
+	defer srv.Close()
+
+	file := filepath.Join(t.TempDir(), "cookies.json")
+	jar := &config.CookieJarConfig{Name: "login-then-fetch", File: file}
+
+	_, err := connectors.NewAPI(srv.URL+"/login", &config.ServerConnectorConfig{Method: http.MethodPost, CookieJar: jar}, nil).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+
+	body, err := connectors.NewAPI(srv.URL+"/me", &config.ServerConnectorConfig{Method: http.MethodGet, CookieJar: jar}, nil).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"user":"john"}`, string(body))
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"name": "session"`)
+	assert.Contains(t, string(content), `"domain": "127.0.0.1"`)
+	assert.Contains(t, string(content), `"origins": []`)
+
+	// a jar with another name reloads the persisted session from the file
+	body, err = connectors.NewAPI(srv.URL+"/me", &config.ServerConnectorConfig{
+		Method:    http.MethodGet,
+		CookieJar: &config.CookieJarConfig{Name: "login-then-fetch-restarted", File: file},
+	}, nil).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"user":"john"}`, string(body))
+}
+
+func TestApiConnectorCookieJarImportStorageState(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("sid")
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(cookie.Value))
+	}))
+	defer srv.Close()
+
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, oauthflow.SaveJSONFile(stateFile, map[string]interface{}{
+		"cookies": []map[string]interface{}{
+			{"name": "sid", "value": "from-browser", "domain": "127.0.0.1", "path": "/", "expires": -1, "httpOnly": true, "secure": false, "sameSite": "Lax"},
+			{"name": "old", "value": "expired", "domain": "127.0.0.1", "path": "/", "expires": 1, "httpOnly": false, "secure": false, "sameSite": "Lax"},
+		},
+		"origins": []interface{}{},
+	}))
+
+	body, err := connectors.NewAPI(srv.URL, &config.ServerConnectorConfig{
+		Method:    http.MethodGet,
+		CookieJar: &config.CookieJarConfig{Name: "imported", ImportStorageState: stateFile},
+	}, nil).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "from-browser", string(body))
+}
+
+func TestApiConnectorCookieJarIdentity(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a/set":
+			http.SetCookie(w, &http.Cookie{Name: "pref", Value: "a", Path: "/a"})
+			http.SetCookie(w, &http.Cookie{Name: "sid", Value: "old", Path: "/", Domain: "127.0.0.1"})
+		case "/b/set":
+			http.SetCookie(w, &http.Cookie{Name: "pref", Value: "b", Path: "/b"})
+			http.SetCookie(w, &http.Cookie{Name: "sid", Value: "new", Path: "/"})
+		case "/logout":
+			http.SetCookie(w, &http.Cookie{Name: "pref", Path: "/a", MaxAge: -1})
+		}
+	}))
+	defer srv.Close()
+
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, oauthflow.SaveJSONFile(stateFile, map[string]interface{}{
+		"cookies": []map[string]interface{}{
+			{"name": "token", "value": "domain", "domain": ".example.com", "path": "/", "expires": -1},
+			{"name": "token", "value": "host", "domain": "example.com", "path": "/", "expires": -1},
+		},
+		"origins": []interface{}{},
+	}))
+
+	file := filepath.Join(t.TempDir(), "cookies.json")
+	jar := &config.CookieJarConfig{Name: "identity", File: file, ImportStorageState: stateFile}
+	persisted := func(path string) []string {
+		_, err := connectors.NewAPI(srv.URL+path, &config.ServerConnectorConfig{Method: http.MethodGet, CookieJar: jar}, nil).Get(context.Background(), nil, nil, nil)
+		require.NoError(t, err)
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		state := struct {
+			Cookies []struct {
+				Name   string `json:"name"`
+				Value  string `json:"value"`
+				Domain string `json:"domain"`
+				Path   string `json:"path"`
+			} `json:"cookies"`
+		}{}
+		require.NoError(t, json.Unmarshal(content, &state))
+		var cookies []string
+		for _, cookie := range state.Cookies {
+			cookies = append(cookies, cookie.Name+"="+cookie.Value+" "+cookie.Domain+cookie.Path)
+		}
+		return cookies
+	}
+
+	persisted("/a/set")
+	// same name on other paths are separate cookies, host-only and domain
+	// cookies of one domain replace each other like in the jar
+	assert.ElementsMatch(t, []string{
+		"token=host example.com/",
+		"sid=new 127.0.0.1/",
+		"pref=a 127.0.0.1/a",
+		"pref=b 127.0.0.1/b",
+	}, persisted("/b/set"))
+
+	assert.ElementsMatch(t, []string{
+		"token=host example.com/",
+		"sid=new 127.0.0.1/",
+		"pref=b 127.0.0.1/b",
+	}, persisted("/logout"))
+}
+
+func TestApiConnectorFormBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+		require.NoError(t, r.ParseForm())
+		_, _ = w.Write([]byte(r.PostForm.Get("q") + "|" + r.PostForm.Get("page")))
+	}))
+	defer srv.Close()
+
+	body, err := connectors.NewAPI(srv.URL, &config.ServerConnectorConfig{
+		Method: http.MethodPost,
+		Form: map[string]string{
+			"q":    "{PL} & more",
+			"page": "{INDEX}",
+		},
+	}, nil).Get(context.Background(), builder.PureString("shoes"), &[]uint32{2}[0], nil)
+	require.NoError(t, err)
+	assert.Equal(t, "shoes & more|2", string(body))
+}
+
+func TestApiConnectorMultipartBody(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "report.json"), []byte(`{"a":1}`), 0o600))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "shoes", r.FormValue("title"))
+
+		file, header, err := r.FormFile("upload")
+		require.NoError(t, err)
+		defer file.Close()
+		assert.Equal(t, "report.json", header.Filename)
+		assert.Equal(t, "application/json", header.Header.Get("Content-Type"))
+
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		_, _ = w.Write(content)
+	}))
+	defer srv.Close()
+
+	body, err := connectors.NewAPI(srv.URL, &config.ServerConnectorConfig{
+		Method: http.MethodPost,
+		Headers: map[string]string{
+			"Content-Type": "text/plain",
+		},
+		Multipart: []*config.MultipartPart{
+			{Name: "title", Value: "{PL}"},
+			{Name: "upload", File: filepath.Join(dir, "report.json")},
+		},
+	}, nil).Get(context.Background(), builder.PureString("shoes"), nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(body))
+}
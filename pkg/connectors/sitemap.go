@@ -0,0 +1,307 @@
+package connectors
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/utils"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	defaultSitemapDepth = 5
+	robotsSitemapPrefix = "sitemap:"
+)
+
+var (
+	errSitemapFormat = errors.New("unknown sitemap format")
+	errSitemapDate   = errors.New("invalid sitemap date")
+
+	// sitemapDateLayouts are the W3C datetime variants allowed for lastmod
+	sitemapDateLayouts = []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04Z07:00",
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+		"2006-01",
+		"2006",
+	}
+)
+
+type sitemapConnector struct {
+	url       string
+	cfg       *config.SitemapConnectorConfig
+	serverCfg *config.ServerConnectorConfig
+	logger    logger.Logger
+}
+
+type sitemapEntry struct {
+	Loc        string   `json:"loc"`
+	Lastmod    string   `json:"lastmod"`
+	Changefreq string   `json:"changefreq"`
+	Priority   *float64 `json:"priority"`
+}
+
+type sitemapLocation struct {
+	Loc        string `xml:"loc"`
+	Lastmod    string `xml:"lastmod"`
+	Changefreq string `xml:"changefreq"`
+	Priority   string `xml:"priority"`
+}
+
+// sitemapDocument is <urlset> or <sitemapindex>
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapLocation `xml:"url"`
+	Sitemaps []sitemapLocation `xml:"sitemap"`
+}
+
+// sitemapWalk is the state of one Get: visited sitemaps, collected entries and filters
+type sitemapWalk struct {
+	filter  *regexp.Regexp
+	after   *time.Time
+	limit   int
+	visited map[string]bool
+	seen    map[string]bool
+	entries []*sitemapEntry
+}
+
+func (w *sitemapWalk) full() bool {
+	return w.limit > 0 && len(w.entries) >= w.limit
+}
+
+func (w *sitemapWalk) add(location sitemapLocation) {
+	loc := strings.TrimSpace(location.Loc)
+	if loc == "" || w.seen[loc] || w.full() {
+		return
+	}
+	if w.filter != nil && !w.filter.MatchString(loc) {
+		return
+	}
+
+	lastmod := strings.TrimSpace(location.Lastmod)
+	if w.after != nil {
+		modified, err := parseSitemapDate(lastmod)
+		if err != nil || !modified.After(*w.after) {
+			return
+		}
+	}
+
+	entry := &sitemapEntry{
+		Loc:        loc,
+		Lastmod:    lastmod,
+		Changefreq: strings.TrimSpace(location.Changefreq),
+	}
+	if priority, err := strconv.ParseFloat(strings.TrimSpace(location.Priority), 64); err == nil {
+		entry.Priority = &priority
+	}
+
+	w.seen[loc] = true
+	w.entries = append(w.entries, entry)
+}
+
+func NewSitemap(url string, cfg *config.SitemapConnectorConfig) *sitemapConnector {
+	serverCfg := &config.ServerConnectorConfig{}
+	if cfg.Server != nil {
+		copied := *cfg.Server
+		serverCfg = &copied
+	}
+	serverCfg.Method = http.MethodGet
+	serverCfg.Body = ""
+	serverCfg.JsonRawBody = nil
+	serverCfg.Form = nil
+	serverCfg.Multipart = nil
+
+	return &sitemapConnector{
+		url:       url,
+		cfg:       cfg,
+		serverCfg: serverCfg,
+		logger:    logger.Null,
+	}
+}
+
+func (s *sitemapConnector) WithLogger(logger logger.Logger) *sitemapConnector {
+	s.logger = logger
+	return s
+}
+
+func (s *sitemapConnector) Get(ctx context.Context, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) ([]byte, error) {
+	formattedURL := utils.Format(s.url, parsedValue, index, input)
+	if formattedURL == "" {
+		return nil, errEmpty
+	}
+
+	walk := &sitemapWalk{
+		limit:   int(s.cfg.MaxURLs),
+		visited: make(map[string]bool),
+		seen:    make(map[string]bool),
+		entries: []*sitemapEntry{},
+	}
+
+	if s.cfg.Filter != "" {
+		filter, err := regexp.Compile(s.cfg.Filter)
+		if err != nil {
+			s.logger.Errorw("unable to compile sitemap filter", "filter", s.cfg.Filter, "error", err.Error())
+			return nil, err
+		}
+		walk.filter = filter
+	}
+
+	if value := strings.TrimSpace(utils.Format(s.cfg.LastmodAfter, parsedValue, index, input)); value != "" {
+		after, err := parseSitemapDate(value)
+		if err != nil {
+			s.logger.Errorw("unable to parse lastmod_after", "value", value, "error", err.Error())
+			return nil, err
+		}
+		walk.after = &after
+	}
+
+	if err := s.walk(ctx, walk, formattedURL, 0, parsedValue, index, input); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(walk.entries)
+}
+
+// walk fetches the sitemap and follows the nested ones; the root is depth 0,
+// so max depth counts the nested levels below it. Only the error of the root
+// sitemap is returned, broken nested sitemaps are logged and skipped
+func (s *sitemapConnector) walk(ctx context.Context, walk *sitemapWalk, sitemapURL string, depth uint32, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) error {
+	maxDepth := uint32(defaultSitemapDepth)
+	if s.cfg.MaxDepth > 0 {
+		maxDepth = s.cfg.MaxDepth
+	}
+	if walk.visited[sitemapURL] || walk.full() {
+		return nil
+	}
+	walk.visited[sitemapURL] = true
+
+	document, err := s.fetch(ctx, sitemapURL, parsedValue, index, input)
+	if err != nil {
+		if depth == 0 {
+			return err
+		}
+		s.logger.Errorw("unable to read nested sitemap", "url", sitemapURL, "error", err.Error())
+		return nil
+	}
+
+	for _, location := range document.URLs {
+		walk.add(location)
+	}
+	for _, nested := range document.Sitemaps {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		if walk.full() {
+			break
+		}
+		if depth >= maxDepth {
+			s.logger.Infow("skip nested sitemaps, max depth reached", "url", sitemapURL, "depth", fmt.Sprintf("%d", depth))
+			break
+		}
+		_ = s.walk(ctx, walk, resolveSitemapURL(sitemapURL, nested.Loc), depth+1, parsedValue, index, input)
+	}
+
+	return nil
+}
+
+func (s *sitemapConnector) fetch(ctx context.Context, sitemapURL string, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) (*sitemapDocument, error) {
+	api := &apiConnector{
+		url:    sitemapURL,
+		cfg:    s.serverCfg,
+		logger: s.logger,
+	}
+	_, body, err := api.get(ctx, parsedValue, index, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// sitemap.xml.gz is served as is, without Content-Encoding
+	body, err = extract(body, config.ArchiveAuto, "", defaultArchiveMaxSize)
+	if err != nil {
+		s.logger.Errorw("unable to decompress sitemap", "url", sitemapURL, "error", err.Error())
+		return nil, err
+	}
+
+	document, err := parseSitemap(body)
+	if err != nil {
+		s.logger.Errorw("unable to parse sitemap", "url", sitemapURL, "error", err.Error())
+		return nil, err
+	}
+	return document, nil
+}
+
+// parseSitemap reads xml sitemaps and indexes; other bodies are text
+// sitemaps (one url per line) or robots.txt with "Sitemap: url" lines
+func parseSitemap(body []byte) (*sitemapDocument, error) {
+	body = bytes.TrimSpace(bytes.TrimPrefix(body, utf8BOM))
+	if bytes.HasPrefix(body, []byte("<")) {
+		decoder := xml.NewDecoder(bytes.NewReader(body))
+		decoder.Strict = false
+		decoder.CharsetReader = charset.NewReaderLabel
+
+		document := &sitemapDocument{}
+		if err := decoder.Decode(document); err != nil {
+			return nil, err
+		}
+		if root := document.XMLName.Local; root != "urlset" && root != "sitemapindex" {
+			return nil, fmt.Errorf("%w: <%s>", errSitemapFormat, root)
+		}
+		return document, nil
+	}
+
+	document := &sitemapDocument{}
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		lower := strings.ToLower(line)
+		switch {
+		case strings.HasPrefix(lower, robotsSitemapPrefix):
+			document.Sitemaps = append(document.Sitemaps, sitemapLocation{Loc: strings.TrimSpace(line[len(robotsSitemapPrefix):])})
+		case strings.HasPrefix(lower, "http://"), strings.HasPrefix(lower, "https://"):
+			document.URLs = append(document.URLs, sitemapLocation{Loc: line})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// resolveSitemapURL tolerates relative locations of sloppy indexes
+func resolveSitemapURL(base string, loc string) string {
+	loc = strings.TrimSpace(loc)
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return loc
+	}
+	resolved, err := baseURL.Parse(loc)
+	if err != nil {
+		return loc
+	}
+	return resolved.String()
+}
+
+func parseSitemapDate(value string) (time.Time, error) {
+	for _, layout := range sitemapDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q", errSitemapDate, value)
+}
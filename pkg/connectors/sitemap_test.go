@@ -0,0 +1,139 @@
+package connectors_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/connectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gzipBody(t *testing.T, body string) []byte {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write([]byte(body))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func newSitemapServer(t *testing.T) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			_, _ = w.Write([]byte("User-agent: *\nDisallow: /cart\nSitemap: " + srv.URL + "/sitemap_index.xml\n"))
+		case "/sitemap_index.xml":
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<sitemap><loc>` + srv.URL + `/products.xml.gz</loc></sitemap>
+	<sitemap><loc>/pages.xml</loc></sitemap>
+	<sitemap><loc>` + srv.URL + `/missing.xml</loc></sitemap>
+	<sitemap><loc>` + srv.URL + `/sitemap_index.xml</loc></sitemap>
+</sitemapindex>`))
+		case "/products.xml.gz":
+			_, _ = w.Write(gzipBody(t, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>https://shop.com/product/1</loc><lastmod>2024-05-01</lastmod><changefreq>daily</changefreq><priority>0.8</priority></url>
+	<url><loc>https://shop.com/product/2</loc><lastmod>2023-01-01T10:00:00+00:00</lastmod></url>
+	<url><loc>https://shop.com/product/3</loc></url>
+</urlset>`))
+		case "/pages.xml":
+			_, _ = w.Write([]byte(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>https://shop.com/about</loc><lastmod>2024-06-01</lastmod></url>
+	<url><loc>https://shop.com/product/1</loc><lastmod>2024-05-01</lastmod></url>
+</urlset>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return srv
+}
+
+func TestSitemapConnector(t *testing.T) {
+	srv := newSitemapServer(t)
+	defer srv.Close()
+
+	body, err := connectors.NewSitemap(srv.URL+"/robots.txt", &config.SitemapConnectorConfig{}).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"loc": "https://shop.com/product/1", "lastmod": "2024-05-01", "changefreq": "daily", "priority": 0.8},
+		{"loc": "https://shop.com/product/2", "lastmod": "2023-01-01T10:00:00+00:00", "changefreq": "", "priority": null},
+		{"loc": "https://shop.com/product/3", "lastmod": "", "changefreq": "", "priority": null},
+		{"loc": "https://shop.com/about", "lastmod": "2024-06-01", "changefreq": "", "priority": null}
+	]`, string(body))
+}
+
+func TestSitemapConnectorFilters(t *testing.T) {
+	srv := newSitemapServer(t)
+	defer srv.Close()
+
+	body, err := connectors.NewSitemap(srv.URL+"/sitemap_index.xml", &config.SitemapConnectorConfig{
+		Filter:       `/product/\d+$`,
+		LastmodAfter: "2024-01-01",
+	}).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"loc": "https://shop.com/product/1", "lastmod": "2024-05-01", "changefreq": "daily", "priority": 0.8}]`, string(body))
+
+	body, err = connectors.NewSitemap(srv.URL+"/sitemap_index.xml", &config.SitemapConnectorConfig{
+		MaxURLs: 2,
+	}).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"loc": "https://shop.com/product/1", "lastmod": "2024-05-01", "changefreq": "daily", "priority": 0.8},
+		{"loc": "https://shop.com/product/2", "lastmod": "2023-01-01T10:00:00+00:00", "changefreq": "", "priority": null}
+	]`, string(body))
+
+	body, err = connectors.NewSitemap(srv.URL+"/robots.txt", &config.SitemapConnectorConfig{
+		MaxDepth: 1,
+	}).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(body))
+}
+
+func TestSitemapConnectorStopsFetching(t *testing.T) {
+	var (
+		mutex sync.Mutex
+		paths []string
+	)
+	srv := newSitemapServer(t)
+	defer srv.Close()
+	handler := srv.Config.Handler
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		paths = append(paths, r.URL.Path)
+		mutex.Unlock()
+		handler.ServeHTTP(w, r)
+	})
+
+	_, err := connectors.NewSitemap(srv.URL+"/sitemap_index.xml", &config.SitemapConnectorConfig{
+		MaxURLs: 2,
+	}).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/sitemap_index.xml", "/products.xml.gz"}, paths)
+
+	paths = nil
+	_, err = connectors.NewSitemap(srv.URL+"/robots.txt", &config.SitemapConnectorConfig{
+		MaxDepth: 1,
+	}).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/robots.txt", "/sitemap_index.xml"}, paths)
+}
+
+func TestSitemapConnectorErrors(t *testing.T) {
+	srv := newSitemapServer(t)
+	defer srv.Close()
+
+	_, err := connectors.NewSitemap(srv.URL+"/sitemap_index.xml", &config.SitemapConnectorConfig{Filter: "("}).Get(context.Background(), nil, nil, nil)
+	assert.Error(t, err)
+
+	_, err = connectors.NewSitemap(srv.URL+"/sitemap_index.xml", &config.SitemapConnectorConfig{LastmodAfter: "yesterday"}).Get(context.Background(), nil, nil, nil)
+	assert.ErrorContains(t, err, "invalid sitemap date")
+}
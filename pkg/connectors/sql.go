@@ -0,0 +1,76 @@
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/sqldb"
+	"github.com/PxyUp/fitter/pkg/utils"
+)
+
+type sqlConnector struct {
+	cfg    *config.SQLConnectorConfig
+	logger logger.Logger
+}
+
+func NewSQL(cfg *config.SQLConnectorConfig) *sqlConnector {
+	return &sqlConnector{
+		cfg:    cfg,
+		logger: logger.Null,
+	}
+}
+
+func (s *sqlConnector) WithLogger(logger logger.Logger) *sqlConnector {
+	s.logger = logger
+	return s
+}
+
+func (s *sqlConnector) Get(ctx context.Context, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) ([]byte, error) {
+	db, err := sqldb.Open(s.cfg.Driver, utils.Format(s.cfg.DSN, parsedValue, index, input))
+	if err != nil {
+		s.logger.Errorw("unable to open database", "driver", string(s.cfg.Driver), "error", err.Error())
+		return nil, err
+	}
+
+	args := make([]interface{}, len(s.cfg.Args))
+	for i, arg := range s.cfg.Args {
+		if arg == nil {
+			continue
+		}
+		value := arg.Value
+		if len(arg.Raw) > 0 {
+			value = string(arg.Raw)
+		}
+		args[i] = sqldb.Arg(builder.Static(&builder.StaticCfg{
+			Type:  arg.Type,
+			Value: utils.Format(value, parsedValue, index, input),
+		}))
+	}
+
+	s.logger.Infow("running sql query", "driver", string(s.cfg.Driver), "query", s.cfg.Query)
+	rows, err := db.QueryContext(ctx, s.cfg.Query, args...)
+	if err != nil {
+		s.logger.Errorw("unable to run sql query", "query", s.cfg.Query, "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	// rows are added one by one, so a huge result stops at the body limit
+	result := newJSONArray(ctx)
+	err = sqldb.EachRow(rows, func(row map[string]interface{}) (bool, error) {
+		raw, errRow := json.Marshal(row)
+		if errRow != nil {
+			return false, errRow
+		}
+		return result.add(string(raw))
+	})
+	if err != nil {
+		s.logger.Errorw("unable to read sql rows", "query", s.cfg.Query, "error", err.Error())
+		return nil, err
+	}
+
+	return result.bytes(), nil
+}
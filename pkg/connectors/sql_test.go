@@ -0,0 +1,65 @@
+package connectors_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/connectors"
+	"github.com/PxyUp/fitter/pkg/sqldb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLConnectorSQLite(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "shop.db")
+	db, err := sqldb.Open(config.SQLite, dsn)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE products (id INTEGER PRIMARY KEY, name TEXT, price REAL, tags TEXT)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO products (id, name, price, tags) VALUES (1, 'shoes', 10.5, NULL), (2, 'hat', 3, 'summer'), (3, 'x''); DROP TABLE products; --', 1, NULL)`)
+	require.NoError(t, err)
+
+	t.Setenv("SHOP_DB", dsn)
+	connector := connectors.NewSQL(&config.SQLConnectorConfig{
+		Driver: config.SQLite,
+		DSN:    "{{{FromEnv=SHOP_DB}}}",
+		Query:  `SELECT id, name, price, tags FROM products WHERE price >= ? AND name != ? ORDER BY id`,
+		Args: []*config.StaticGeneratedFieldConfig{
+			{Type: config.Float, Value: "{PL}"},
+			{Type: config.String, Value: "{{{FromInput=skip}}}"},
+		},
+	})
+
+	body, err := connector.Get(context.Background(), builder.Number(3), nil, builder.ToJsonableFromString(`{"skip": "none"}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"id": 1, "name": "shoes", "price": 10.5, "tags": null}, {"id": 2, "name": "hat", "price": 3, "tags": "summer"}]`, string(body))
+
+	// placeholders are bound, not interpolated into the query
+	connector = connectors.NewSQL(&config.SQLConnectorConfig{
+		Driver: config.SQLite,
+		DSN:    dsn,
+		Query:  `SELECT name FROM products WHERE name = ?`,
+		Args: []*config.StaticGeneratedFieldConfig{
+			{Type: config.String, Value: "{PL}"},
+		},
+	})
+	body, err = connector.Get(context.Background(), builder.PureString("x'); DROP TABLE products; --"), nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"name": "x'); DROP TABLE products; --"}]`, string(body))
+
+	body, err = connector.Get(context.Background(), builder.PureString("shoes"), nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"name": "shoes"}]`, string(body))
+}
+
+func TestSQLConnectorUnsupportedDriver(t *testing.T) {
+	_, err := connectors.NewSQL(&config.SQLConnectorConfig{
+		Driver: "oracle",
+		DSN:    "dsn",
+		Query:  "SELECT 1",
+	}).Get(context.Background(), nil, nil, nil)
+	assert.Error(t, err)
+}
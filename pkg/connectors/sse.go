@@ -0,0 +1,156 @@
+package connectors
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/utils"
+)
+
+const (
+	defaultSSEEvent = "message"
+	maxSSELineSize  = 1024 * 1024
+)
+
+var (
+	// streamClient has no timeout: the collection window is bound by the context
+	streamClient = &http.Client{}
+)
+
+type sseConnector struct {
+	url    string
+	cfg    *config.SSEConnectorConfig
+	logger logger.Logger
+}
+
+func NewSSE(url string, cfg *config.SSEConnectorConfig) *sseConnector {
+	return &sseConnector{
+		url:    url,
+		cfg:    cfg,
+		logger: logger.Null,
+	}
+}
+
+func (s *sseConnector) WithLogger(logger logger.Logger) *sseConnector {
+	s.logger = logger
+	return s
+}
+
+func (s *sseConnector) Get(ctx context.Context, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) ([]byte, error) {
+	formattedURL := utils.Format(s.url, parsedValue, index, input)
+	if formattedURL == "" {
+		return nil, errEmpty
+	}
+
+	method := s.cfg.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	windowCtx, cancel := streamWindow(ctx, s.cfg.Duration)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(windowCtx, method, formattedURL, bytes.NewBufferString(utils.Format(s.cfg.Body, parsedValue, index, input)))
+	if err != nil {
+		s.logger.Errorw("unable to create sse request", "error", err.Error())
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	for k, v := range s.cfg.Headers {
+		req.Header.Set(utils.Format(k, parsedValue, index, input), utils.Format(v, parsedValue, index, input))
+	}
+
+	s.logger.Infow("connecting to sse stream", "url", formattedURL)
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		if ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+			return []byte("[]"), nil
+		}
+		s.logger.Errorw("unable to connect to sse stream", "url", formattedURL, "error", err.Error())
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		err = fmt.Errorf("sse stream returned status %s", resp.Status)
+		s.logger.Errorw("unable to connect to sse stream", "url", formattedURL, "error", err.Error())
+		return nil, err
+	}
+
+	collector := newMessageCollector(ctx, s.cfg.MaxMessages, s.cfg.Until, input, s.logger)
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSSELineSize)
+
+	event := ""
+	var data []string
+	// pending is the size of the data lines of the event which is not dispatched yet
+	pending := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// blank line dispatches the event
+			name := event
+			if name == "" {
+				name = defaultSSEEvent
+			}
+			if len(data) > 0 && (s.cfg.Event == "" || s.cfg.Event == name) {
+				done, errAdd := collector.add([]byte(strings.Join(data, "\n")))
+				if errAdd != nil {
+					s.logger.Errorw("sse messages are too large", "url", formattedURL, "error", errAdd.Error())
+					return nil, errAdd
+				}
+				if done {
+					return collector.result(), nil
+				}
+			}
+			event = ""
+			data = nil
+			pending = 0
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data = append(data, value)
+			pending += len(value) + 1
+			if !collector.messages.fits(pending) {
+				// an endless event must not be buffered
+				if collector.messages.limit.truncate {
+					return collector.result(), nil
+				}
+				err = collector.messages.limit.exceeded()
+				s.logger.Errorw("sse event is too large", "url", formattedURL, "error", err.Error())
+				return nil, err
+			}
+		}
+	}
+
+	// the window is over or the server closed the stream
+	if err = scanner.Err(); err != nil && windowCtx.Err() == nil {
+		s.logger.Errorw("unable to read sse stream", "url", formattedURL, "error", err.Error())
+		return nil, err
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return collector.result(), nil
+}
@@ -13,7 +13,8 @@ type staticConnector struct {
 	logger logger.Logger
 }
 
-func (j *staticConnector) Get(_ context.Context, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) ([]byte, error) {
+func (j *staticConnector) Get(ctx context.Context, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) ([]byte, error) {
+	setContentType(ctx, decodedContentType)
 	if len(j.cfg.Raw) != 0 {
 		return []byte(utils.Format(string(j.cfg.Raw), parsedValue, index, input)), nil
 	}
@@ -0,0 +1,76 @@
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/utils"
+)
+
+const defaultStreamDuration = 60 * time.Second
+
+// messageCollector gathers the messages of a stream connector until the
+// max amount of messages is reached, the until expression matches or the
+// body limit is reached
+type messageCollector struct {
+	maxMessages uint32
+	until       string
+	input       builder.Interfacable
+	logger      logger.Logger
+
+	messages *jsonArray
+}
+
+func newMessageCollector(ctx context.Context, maxMessages uint32, until string, input builder.Interfacable, logger logger.Logger) *messageCollector {
+	return &messageCollector{
+		maxMessages: maxMessages,
+		until:       until,
+		input:       input,
+		logger:      logger,
+		messages:    newJSONArray(ctx),
+	}
+}
+
+// streamWindow bounds the collection by the configured duration
+func streamWindow(ctx context.Context, duration uint32) (context.Context, context.CancelFunc) {
+	window := defaultStreamDuration
+	if duration > 0 {
+		window = time.Duration(duration) * time.Second
+	}
+	return context.WithTimeout(ctx, window)
+}
+
+// add stores the message (json messages as is, other ones as json strings)
+// and reports whether the collection is complete; a message over the body
+// limit completes it in truncate mode and fails it otherwise
+func (c *messageCollector) add(message []byte) (bool, error) {
+	raw := string(message)
+	if !json.Valid(message) {
+		quoted, _ := json.Marshal(raw)
+		raw = string(quoted)
+	}
+	added, err := c.messages.add(raw)
+	if err != nil || !added {
+		return true, err
+	}
+
+	count := uint32(len(c.messages.elements))
+	if c.until != "" {
+		index := count - 1
+		matched, err := utils.ProcessCondition(c.until, builder.ToJsonableFromString(raw), &index, c.input)
+		if err != nil {
+			c.logger.Errorw("unable to process until expression", "expression", c.until, "error", err.Error())
+		} else if matched {
+			return true, nil
+		}
+	}
+
+	return c.maxMessages > 0 && count >= c.maxMessages, nil
+}
+
+func (c *messageCollector) result() []byte {
+	return c.messages.bytes()
+}
@@ -0,0 +1,111 @@
+package connectors_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/connectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func sseServer(t *testing.T, events []string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		for _, event := range events {
+			_, _ = fmt.Fprint(w, event)
+			flusher.Flush()
+		}
+		<-r.Context().Done()
+	}))
+}
+
+func TestSSEConnectorMaxMessages(t *testing.T) {
+	srv := sseServer(t, []string{
+		": keep-alive\n\n",
+		"event: price\ndata: {\"symbol\": \"BTC\", \"price\": 10}\n\n",
+		"event: heartbeat\ndata: ping\n\n",
+		"event: price\ndata: {\"symbol\": \"BTC\",\ndata: \"price\": 11}\n\n",
+		"event: price\ndata: {\"symbol\": \"BTC\", \"price\": 12}\n\n",
+	})
+	defer srv.Close()
+
+	body, err := connectors.NewSSE(srv.URL, &config.SSEConnectorConfig{
+		Event:       "price",
+		MaxMessages: 2,
+		Duration:    5,
+	}).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"symbol": "BTC", "price": 10}, {"symbol": "BTC", "price": 11}]`, string(body))
+}
+
+func TestSSEConnectorUntilAndDuration(t *testing.T) {
+	srv := sseServer(t, []string{
+		"data: {\"status\": \"pending\"}\n\n",
+		"data: {\"status\": \"done\"}\n\n",
+		"data: {\"status\": \"late\"}\n\n",
+	})
+	defer srv.Close()
+
+	body, err := connectors.NewSSE(srv.URL, &config.SSEConnectorConfig{
+		Until:    `fRes.status == "done"`,
+		Duration: 5,
+	}).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"status": "pending"}, {"status": "done"}]`, string(body))
+
+	start := time.Now()
+	body, err = connectors.NewSSE(srv.URL, &config.SSEConnectorConfig{
+		Duration: 1,
+	}).Get(context.Background(), nil, nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"status": "pending"}, {"status": "done"}, {"status": "late"}]`, string(body))
+	assert.Less(t, time.Since(start), 3*time.Second)
+}
+
+func TestWebSocketConnectorSubscribe(t *testing.T) {
+	srv := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		assert.Equal(t, "secret", conn.Request().Header.Get("X-Token"))
+
+		var subscribe string
+		require.NoError(t, websocket.Message.Receive(conn, &subscribe))
+		assert.JSONEq(t, `{"op": "subscribe", "channel": "BTC"}`, subscribe)
+
+		for i := 1; i <= 5; i++ {
+			if err := websocket.Message.Send(conn, fmt.Sprintf(`{"price": %d}`, i)); err != nil {
+				return
+			}
+		}
+		_ = websocket.Message.Send(conn, "not json")
+		time.Sleep(2 * time.Second)
+	}))
+	defer srv.Close()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	body, err := connectors.NewWebSocket(url, &config.WebSocketConnectorConfig{
+		Headers:      map[string]string{"X-Token": "secret"},
+		SubscribeRaw: []byte(`{"op": "subscribe", "channel": "{PL}"}`),
+		MaxMessages:  3,
+	}).Get(context.Background(), builder.PureString("BTC"), nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"price": 1}, {"price": 2}, {"price": 3}]`, string(body))
+
+	body, err = connectors.NewWebSocket(url, &config.WebSocketConnectorConfig{
+		Headers:      map[string]string{"X-Token": "secret"},
+		SubscribeRaw: []byte(`{"op": "subscribe", "channel": "{PL}"}`),
+		Duration:     1,
+	}).Get(context.Background(), builder.PureString("BTC"), nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"price": 1}, {"price": 2}, {"price": 3}, {"price": 4}, {"price": 5}, "not json"]`, string(body))
+}
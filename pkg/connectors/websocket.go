@@ -0,0 +1,142 @@
+package connectors
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/utils"
+	"golang.org/x/net/websocket"
+)
+
+type webSocketConnector struct {
+	url    string
+	cfg    *config.WebSocketConnectorConfig
+	logger logger.Logger
+}
+
+func NewWebSocket(url string, cfg *config.WebSocketConnectorConfig) *webSocketConnector {
+	return &webSocketConnector{
+		url:    url,
+		cfg:    cfg,
+		logger: logger.Null,
+	}
+}
+
+func (w *webSocketConnector) WithLogger(logger logger.Logger) *webSocketConnector {
+	w.logger = logger
+	return w
+}
+
+func (w *webSocketConnector) Get(ctx context.Context, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) ([]byte, error) {
+	formattedURL := utils.Format(w.url, parsedValue, index, input)
+	if formattedURL == "" {
+		return nil, errEmpty
+	}
+
+	origin := utils.Format(w.cfg.Origin, parsedValue, index, input)
+	if origin == "" {
+		origin = webSocketOrigin(formattedURL)
+	}
+
+	wsCfg, err := websocket.NewConfig(formattedURL, origin)
+	if err != nil {
+		w.logger.Errorw("unable to create websocket config", "url", formattedURL, "error", err.Error())
+		return nil, err
+	}
+	for k, v := range w.cfg.Headers {
+		wsCfg.Header.Set(utils.Format(k, parsedValue, index, input), utils.Format(v, parsedValue, index, input))
+	}
+
+	windowCtx, cancel := streamWindow(ctx, w.cfg.Duration)
+	defer cancel()
+
+	w.logger.Infow("connecting to websocket", "url", formattedURL)
+	conn, err := wsCfg.DialContext(windowCtx)
+	if err != nil {
+		if ctx.Err() == nil && windowCtx.Err() != nil {
+			return []byte("[]"), nil
+		}
+		w.logger.Errorw("unable to connect to websocket", "url", formattedURL, "error", err.Error())
+		return nil, err
+	}
+	defer conn.Close()
+
+	// unblock the pending read when the window is over or the parent is cancelled
+	go func() {
+		<-windowCtx.Done()
+		_ = conn.SetReadDeadline(time.Now())
+	}()
+
+	subscribe := w.cfg.Subscribe
+	if len(w.cfg.SubscribeRaw) > 0 {
+		subscribe = string(w.cfg.SubscribeRaw)
+	}
+	if subscribe != "" {
+		err = websocket.Message.Send(conn, utils.Format(subscribe, parsedValue, index, input))
+		if err != nil {
+			w.logger.Errorw("unable to send websocket subscribe message", "url", formattedURL, "error", err.Error())
+			return nil, err
+		}
+	}
+
+	collector := newMessageCollector(ctx, w.cfg.MaxMessages, w.cfg.Until, input, w.logger)
+	if limit := collector.messages.limit; limit.maxBytes > 0 && limit.maxBytes < websocket.DefaultMaxPayloadBytes {
+		// a single frame over the limit is rejected before it is buffered
+		conn.MaxPayloadBytes = int(limit.maxBytes) + 1
+	}
+	for {
+		var message []byte
+		err = websocket.Message.Receive(conn, &message)
+		if errors.Is(err, websocket.ErrFrameTooLarge) {
+			if collector.messages.limit.truncate {
+				return collector.result(), nil
+			}
+			err = collector.messages.limit.exceeded()
+			w.logger.Errorw("websocket message is too large", "url", formattedURL, "error", err.Error())
+			return nil, err
+		}
+		if err != nil {
+			break
+		}
+		done, errAdd := collector.add(message)
+		if errAdd != nil {
+			w.logger.Errorw("websocket messages are too large", "url", formattedURL, "error", errAdd.Error())
+			return nil, errAdd
+		}
+		if done {
+			return collector.result(), nil
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if windowCtx.Err() == nil && !errors.Is(err, io.EOF) {
+		w.logger.Errorw("unable to read websocket message", "url", formattedURL, "error", err.Error())
+		return nil, err
+	}
+
+	// the window is over or the server closed the connection
+	return collector.result(), nil
+}
+
+// webSocketOrigin maps ws(s)://host/path to http(s)://host
+func webSocketOrigin(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "http://localhost"
+	}
+
+	scheme := "http"
+	if parsed.Scheme == "wss" || parsed.Scheme == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + parsed.Host
+}
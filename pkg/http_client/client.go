@@ -1,12 +1,203 @@
 package http_client
 
 import (
+	"container/list"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/PxyUp/fitter/pkg/config"
 	"net/http"
+	"net/url"
+	"os"
+	"sync"
 	"time"
 )
 
+const (
+	clientTimeout = time.Minute * 2
+
+	// maxClients bounds the shared clients: proxy urls can be formatted per
+	// request, so every distinct proxy adds a transport
+	maxClients = 256
+)
+
+var (
+	errInvalidCABundle = errors.New("no certificates found in ca bundle")
+
+	defaultClient = &http.Client{
+		Timeout: clientTimeout,
+	}
+
+	// clients are shared per distinct proxy and transport settings, so every
+	// request with the same settings reuses the pooled connections
+	clients = newClientCache(maxClients)
+)
+
+// clientCache is the lru of shared clients, evicted clients close their idle
+// connections
+type clientCache struct {
+	mu      sync.Mutex
+	limit   int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type cachedClient struct {
+	key    string
+	client *http.Client
+}
+
+func newClientCache(limit int) *clientCache {
+	return &clientCache{
+		limit:   limit,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *clientCache) getOrCreate(key string, create func() (*http.Client, error)) (*http.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		return element.Value.(*cachedClient).client, nil
+	}
+
+	client, err := create()
+	if err != nil {
+		return nil, err
+	}
+
+	c.entries[key] = c.order.PushFront(&cachedClient{key: key, client: client})
+	if c.order.Len() > c.limit {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		evicted := oldest.Value.(*cachedClient)
+		delete(c.entries, evicted.key)
+		// in-flight requests keep their connections, only the idle ones are closed
+		evicted.client.CloseIdleConnections()
+	}
+	return client, nil
+}
+
+// GetDefaultClient returns the shared client with the default pooled transport
 func GetDefaultClient() *http.Client {
+	return defaultClient
+}
+
+// GetClient returns the shared client for the proxy (can be nil) and transport
+// settings (can be nil), creating its pooled transport on the first use
+func GetClient(proxy *url.URL, cfg *config.TransportConfig) (*http.Client, error) {
+	if proxy == nil && cfg == nil {
+		return defaultClient, nil
+	}
+
+	return clients.getOrCreate(clientKey(proxy, cfg), func() (*http.Client, error) {
+		return newClient(proxy, cfg)
+	})
+}
+
+func clientKey(proxy *url.URL, cfg *config.TransportConfig) string {
+	key := ""
+	if proxy != nil {
+		key = proxy.String()
+	}
+	if cfg != nil {
+		key += fmt.Sprintf("|%+v", *cfg)
+	}
+	return key
+}
+
+func newClient(proxy *url.URL, cfg *config.TransportConfig) (*http.Client, error) {
+	if cfg == nil {
+		cfg = &config.TransportConfig{}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxy != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if cfg.MaxIdleConns > 0 {
+		transport.MaxIdleConns = cfg.MaxIdleConns
+	}
+	if cfg.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if cfg.DisableHTTP2 {
+		transport.ForceAttemptHTTP2 = false
+		// a non-nil empty map disables the automatic HTTP/2 upgrade
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+
 	return &http.Client{
-		Timeout: time.Minute * 2,
+		Timeout:       clientTimeout,
+		Transport:     transport,
+		CheckRedirect: redirectPolicy(cfg),
+	}, nil
+}
+
+func newTLSConfig(cfg *config.TransportConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		bundle, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read ca bundle: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, errInvalidCABundle
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+func redirectPolicy(cfg *config.TransportConfig) func(req *http.Request, via []*http.Request) error {
+	maxRedirects := 10
+	if cfg.MaxRedirects > 0 {
+		maxRedirects = int(cfg.MaxRedirects)
+	}
+
+	return func(req *http.Request, via []*http.Request) error {
+		switch cfg.Redirect {
+		case config.RedirectNone:
+			// the redirect response itself becomes the result
+			return http.ErrUseLastResponse
+		case config.RedirectSameHost:
+			if req.URL.Host != via[0].URL.Host {
+				return http.ErrUseLastResponse
+			}
+		}
+
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return nil
 	}
 }
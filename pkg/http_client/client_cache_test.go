@@ -0,0 +1,34 @@
+package http_client
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCacheEvictsOldest(t *testing.T) {
+	cache := newClientCache(2)
+	create := func() (*http.Client, error) {
+		return &http.Client{Transport: &http.Transport{}}, nil
+	}
+
+	first, err := cache.getOrCreate("first", create)
+	require.NoError(t, err)
+	second, err := cache.getOrCreate("second", create)
+	require.NoError(t, err)
+
+	again, err := cache.getOrCreate("first", create)
+	require.NoError(t, err)
+	assert.Same(t, first, again)
+
+	_, err = cache.getOrCreate("third", create)
+	require.NoError(t, err)
+	assert.Equal(t, 2, cache.order.Len())
+	assert.NotContains(t, cache.entries, "second")
+
+	recreated, err := cache.getOrCreate("second", create)
+	require.NoError(t, err)
+	assert.NotSame(t, second, recreated)
+}
@@ -0,0 +1,32 @@
+package http_client_test
+
+import (
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/http_client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/url"
+	"testing"
+)
+
+func TestGetClientShared(t *testing.T) {
+	defaultClient, err := http_client.GetClient(nil, nil)
+	require.NoError(t, err)
+	assert.Same(t, http_client.GetDefaultClient(), defaultClient)
+
+	proxy, err := url.Parse("http://localhost:3128")
+	require.NoError(t, err)
+
+	first, err := http_client.GetClient(proxy, &config.TransportConfig{MaxIdleConns: 10})
+	require.NoError(t, err)
+	second, err := http_client.GetClient(proxy, &config.TransportConfig{MaxIdleConns: 10})
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	other, err := http_client.GetClient(proxy, &config.TransportConfig{MaxIdleConns: 10, DisableHTTP2: true})
+	require.NoError(t, err)
+	assert.NotSame(t, first, other)
+
+	_, err = http_client.GetClient(nil, &config.TransportConfig{CAFile: "missing.pem"})
+	assert.Error(t, err)
+}
@@ -11,6 +11,12 @@ var (
 	chromiumInstance   *semaphore.Weighted
 	dockerContainers   *semaphore.Weighted
 	playwrightInstance *semaphore.Weighted
+	fieldWorkers       *semaphore.Weighted
+	processes          *semaphore.Weighted
+
+	fieldWorkersPerItem uint32
+	sequentialFields    bool
+	maxBodyBytes        int64
 
 	once = &sync.Once{}
 )
@@ -38,21 +44,41 @@ func SetLimits(limits *config.Limits) {
 		setSemaphoreLimit(&chromiumInstance, limits.ChromiumInstance)
 		setSemaphoreLimit(&dockerContainers, limits.DockerContainers)
 		setSemaphoreLimit(&playwrightInstance, limits.PlaywrightInstance)
+		setSemaphoreLimit(&processes, limits.MaxProcesses)
 		setRequestPerHost(limits.HostRequestLimiter)
+		setFieldWorkers(limits)
+		maxBodyBytes = limits.MaxBodyBytes
 	})
 }
 
-// ReplaceLimits swaps the host request limits, bypassing the process-lifetime
-// once semantics of SetLimits. Long-lived embedders that execute many
-// unrelated configs in one process (e.g. the WASM playground) call it before
-// each run so every config gets exactly its own limits. Only in-flight
-// requests keep the semaphores they already acquired.
+func setFieldWorkers(limits *config.Limits) {
+	resetSemaphoreLimit(&fieldWorkers, limits.FieldWorkers)
+	fieldWorkersPerItem = limits.FieldWorkersPerItem
+	sequentialFields = limits.SequentialFields
+}
+
+// ReplaceLimits swaps all limits, bypassing the process-lifetime once
+// semantics of SetLimits. Long-lived embedders that execute many unrelated
+// configs in one process (e.g. the WASM playground) call it before each run
+// so every config gets exactly its own limits. Only in-flight requests keep
+// the semaphores they already acquired.
 func ReplaceLimits(limits *config.Limits) {
-	limitPerHost = make(map[string]*semaphore.Weighted)
 	if limits == nil {
-		return
+		limits = &config.Limits{}
 	}
+	limitPerHost = make(map[string]*semaphore.Weighted)
+	resetSemaphoreLimit(&chromiumInstance, limits.ChromiumInstance)
+	resetSemaphoreLimit(&dockerContainers, limits.DockerContainers)
+	resetSemaphoreLimit(&playwrightInstance, limits.PlaywrightInstance)
+	resetSemaphoreLimit(&processes, limits.MaxProcesses)
 	setRequestPerHost(limits.HostRequestLimiter)
+	setFieldWorkers(limits)
+	maxBodyBytes = limits.MaxBodyBytes
+}
+
+func resetSemaphoreLimit(sem **semaphore.Weighted, count uint32) {
+	*sem = nil
+	setSemaphoreLimit(sem, count)
 }
 
 func HostLimiter(host string) *semaphore.Weighted {
@@ -74,3 +100,24 @@ func PlaywrightLimiter() *semaphore.Weighted {
 func DockerLimiter() *semaphore.Weighted {
 	return dockerContainers
 }
+
+func ProcessLimiter() *semaphore.Weighted {
+	return processes
+}
+
+// MaxBodyBytes is the global limit of the connector body, 0 means unlimited
+func MaxBodyBytes() int64 {
+	return maxBodyBytes
+}
+
+func FieldWorkersLimiter() *semaphore.Weighted {
+	return fieldWorkers
+}
+
+func FieldWorkersPerItem() uint32 {
+	return fieldWorkersPerItem
+}
+
+func SequentialFields() bool {
+	return sequentialFields
+}
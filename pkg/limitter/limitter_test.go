@@ -0,0 +1,37 @@
+package limitter_test
+
+import (
+	"testing"
+
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/limitter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceLimitsResetsAll(t *testing.T) {
+	limitter.ReplaceLimits(&config.Limits{
+		HostRequestLimiter: config.HostRequestLimiter{"example.com": 1},
+		ChromiumInstance:   1,
+		DockerContainers:   1,
+		PlaywrightInstance: 1,
+		MaxProcesses:       1,
+		FieldWorkers:       1,
+		MaxBodyBytes:       10,
+	})
+	assert.NotNil(t, limitter.HostLimiter("example.com"))
+	assert.NotNil(t, limitter.ChromiumLimiter())
+	assert.NotNil(t, limitter.DockerLimiter())
+	assert.NotNil(t, limitter.PlaywrightLimiter())
+	assert.NotNil(t, limitter.ProcessLimiter())
+	assert.NotNil(t, limitter.FieldWorkersLimiter())
+	assert.Equal(t, int64(10), limitter.MaxBodyBytes())
+
+	limitter.ReplaceLimits(nil)
+	assert.Nil(t, limitter.HostLimiter("example.com"))
+	assert.Nil(t, limitter.ChromiumLimiter())
+	assert.Nil(t, limitter.DockerLimiter())
+	assert.Nil(t, limitter.PlaywrightLimiter())
+	assert.Nil(t, limitter.ProcessLimiter())
+	assert.Nil(t, limitter.FieldWorkersLimiter())
+	assert.Zero(t, limitter.MaxBodyBytes())
+}
@@ -21,6 +21,12 @@ type Notifier interface {
 	GetLogger() logger.Logger
 }
 
+// batchNotifier receives all the items of an array result at once, so they
+// can be written together instead of one by one
+type batchNotifier interface {
+	notifyBatch([]*singleRecord, builder.Interfacable) error
+}
+
 func recordToInterfacable(record *singleRecord) builder.Interfacable {
 	if record.Error != nil {
 		return builder.String((*record.Error).Error())
@@ -107,6 +113,10 @@ func Inform(notifier Notifier, name string, result *parser.ParseResult, errResul
 		return err
 	}
 
+	if batch, ok := notifier.(batchNotifier); ok {
+		return batch.notifyBatch(records, input)
+	}
+
 	for _, rec := range records {
 		errNotify := notifier.notify(rec, input)
 		if errNotify != nil {
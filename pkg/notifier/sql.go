@@ -0,0 +1,291 @@
+package notifier
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/sqldb"
+	"github.com/PxyUp/fitter/pkg/utils"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	defaultSQLBatchSize = 100
+	sqlTimeout          = time.Minute
+)
+
+var (
+	_ Notifier      = &sqlNotifier{}
+	_ batchNotifier = &sqlNotifier{}
+
+	errSQLTable   = errors.New("sql notifier: table is required")
+	errSQLColumns = errors.New("sql notifier: at least one column is required")
+	errSQLKey     = errors.New("sql notifier: key is required for upsert")
+	errSQLMode    = errors.New("sql notifier: unsupported mode")
+)
+
+type sqlNotifier struct {
+	logger logger.Logger
+	name   string
+	cfg    *config.SQLNotifierConfig
+
+	mutex   sync.Mutex
+	created bool
+}
+
+// ValidateSQL checks the sql notifier config at load time
+func ValidateSQL(cfg *config.SQLNotifierConfig) error {
+	if cfg.Table == "" {
+		return errSQLTable
+	}
+	if len(cfg.Columns) == 0 {
+		return errSQLColumns
+	}
+
+	for i, column := range cfg.Columns {
+		if column == nil || column.Name == "" {
+			return fmt.Errorf("sql notifier: column %d without name", i)
+		}
+	}
+
+	switch cfg.Mode {
+	case "", config.SQLInsert:
+	case config.SQLUpsert:
+		if len(cfg.Key) == 0 {
+			return errSQLKey
+		}
+	default:
+		return fmt.Errorf("%w: %q", errSQLMode, cfg.Mode)
+	}
+
+	for _, key := range cfg.Key {
+		if findColumn(cfg.Columns, key) == nil {
+			return fmt.Errorf("sql notifier: key %q is not a column", key)
+		}
+	}
+
+	return nil
+}
+
+func findColumn(columns []*config.SQLColumn, name string) *config.SQLColumn {
+	for _, column := range columns {
+		if column != nil && column.Name == name {
+			return column
+		}
+	}
+	return nil
+}
+
+func (s *sqlNotifier) notify(record *singleRecord, input builder.Interfacable) error {
+	return s.notifyBatch([]*singleRecord{record}, input)
+}
+
+func (s *sqlNotifier) notifyBatch(records []*singleRecord, input builder.Interfacable) error {
+	rows := make([][]interface{}, 0, len(records))
+	for _, record := range records {
+		if record.Error != nil {
+			s.logger.Infow("skip error result", "error", (*record.Error).Error())
+			continue
+		}
+		rows = append(rows, s.row(record))
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	db, err := sqldb.Open(s.cfg.Driver, utils.Format(s.cfg.DSN, nil, nil, input))
+	if err != nil {
+		s.logger.Errorw("unable to open database", "driver", string(s.cfg.Driver), "error", err.Error())
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sqlTimeout)
+	defer cancel()
+
+	err = s.createTable(ctx, db)
+	if err != nil {
+		s.logger.Errorw("unable to create table", "table", s.cfg.Table, "error", err.Error())
+		return err
+	}
+
+	batchSize := defaultSQLBatchSize
+	if s.cfg.BatchSize > 0 {
+		batchSize = int(s.cfg.BatchSize)
+	}
+
+	statement := s.insertStatement()
+	for start := 0; start < len(rows); start += batchSize {
+		end := min(start+batchSize, len(rows))
+		err = writeBatch(ctx, db, statement, rows[start:end])
+		if err != nil {
+			s.logger.Errorw("unable to write rows", "table", s.cfg.Table, "error", err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// row extracts the column values of the record
+func (s *sqlNotifier) row(record *singleRecord) []interface{} {
+	body := gjson.ParseBytes(record.Body)
+	values := make([]interface{}, len(s.cfg.Columns))
+	for i, column := range s.cfg.Columns {
+		result := body
+		if column.Path != "" {
+			result = result.Get(column.Path)
+		}
+		if !result.Exists() || result.Type == gjson.Null {
+			continue
+		}
+
+		switch column.Type {
+		case config.Int, config.Int64:
+			values[i] = result.Int()
+		case config.Float, config.Float64:
+			values[i] = result.Float()
+		case config.Bool:
+			values[i] = result.Bool()
+		case config.Object, config.Array:
+			values[i] = result.Raw
+		default:
+			values[i] = result.String()
+		}
+	}
+	return values
+}
+
+func (s *sqlNotifier) createTable(ctx context.Context, db *sql.DB) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.created {
+		return nil
+	}
+
+	definitions := make([]string, 0, len(s.cfg.Columns)+1)
+	for _, column := range s.cfg.Columns {
+		definitions = append(definitions, sqldb.QuoteIdentifier(s.cfg.Driver, column.Name)+" "+sqldb.ColumnType(s.cfg.Driver, column.Type, findKey(s.cfg.Key, column.Name)))
+	}
+	// the key is created in insert mode too, so duplicates fail instead of
+	// silently adding rows
+	if len(s.cfg.Key) > 0 {
+		definitions = append(definitions, "PRIMARY KEY ("+s.quoteAll(s.cfg.Key)+")")
+	}
+
+	_, err := db.ExecContext(ctx, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", sqldb.QuoteIdentifier(s.cfg.Driver, s.cfg.Table), strings.Join(definitions, ", ")))
+	if err != nil {
+		return err
+	}
+
+	s.created = true
+	return nil
+}
+
+func (s *sqlNotifier) insertStatement() string {
+	names := make([]string, len(s.cfg.Columns))
+	placeholders := make([]string, len(s.cfg.Columns))
+	for i, column := range s.cfg.Columns {
+		names[i] = column.Name
+		placeholders[i] = sqldb.Placeholder(s.cfg.Driver, i+1)
+	}
+
+	statement := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", sqldb.QuoteIdentifier(s.cfg.Driver, s.cfg.Table), s.quoteAll(names), strings.Join(placeholders, ", "))
+	if s.cfg.Mode != config.SQLUpsert {
+		return statement
+	}
+
+	var updates []string
+	for _, column := range s.cfg.Columns {
+		if findKey(s.cfg.Key, column.Name) {
+			continue
+		}
+		quoted := sqldb.QuoteIdentifier(s.cfg.Driver, column.Name)
+		if s.cfg.Driver == config.MySQL {
+			updates = append(updates, quoted+" = VALUES("+quoted+")")
+		} else {
+			updates = append(updates, quoted+" = excluded."+quoted)
+		}
+	}
+
+	if s.cfg.Driver == config.MySQL {
+		if len(updates) == 0 {
+			// only key columns: keep the existing row
+			return strings.Replace(statement, "INSERT", "INSERT IGNORE", 1)
+		}
+		return statement + " ON DUPLICATE KEY UPDATE " + strings.Join(updates, ", ")
+	}
+
+	conflict := " ON CONFLICT (" + s.quoteAll(s.cfg.Key) + ")"
+	if len(updates) == 0 {
+		return statement + conflict + " DO NOTHING"
+	}
+	return statement + conflict + " DO UPDATE SET " + strings.Join(updates, ", ")
+}
+
+func findKey(keys []string, name string) bool {
+	for _, key := range keys {
+		if key == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *sqlNotifier) quoteAll(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = sqldb.QuoteIdentifier(s.cfg.Driver, name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// writeBatch writes the rows in one transaction
+func writeBatch(ctx context.Context, db *sql.DB, statement string, rows [][]interface{}) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, statement)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		_, err = stmt.ExecContext(ctx, row...)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *sqlNotifier) GetLogger() logger.Logger {
+	return s.logger
+}
+
+func (s *sqlNotifier) WithLogger(logger logger.Logger) *sqlNotifier {
+	s.logger = logger
+	return s
+}
+
+func NewSQL(name string, cfg *config.SQLNotifierConfig) *sqlNotifier {
+	return &sqlNotifier{
+		logger: logger.Null,
+		name:   name,
+		cfg:    cfg,
+	}
+}
@@ -0,0 +1,87 @@
+package notifier_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/notifier"
+	"github.com/PxyUp/fitter/pkg/parser"
+	"github.com/PxyUp/fitter/pkg/sqldb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLNotifierUpsertBatches(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "results.db")
+	cfg := &config.SQLNotifierConfig{
+		Driver: config.SQLite,
+		DSN:    dsn,
+		Table:  "products",
+		Columns: []*config.SQLColumn{
+			{Name: "sku", Path: "sku"},
+			{Name: "price", Path: "price.value", Type: config.Float},
+			{Name: "stock", Path: "stock", Type: config.Int},
+			{Name: "tags", Path: "tags", Type: config.Array},
+		},
+		Key:       []string{"sku"},
+		Mode:      config.SQLUpsert,
+		BatchSize: 2,
+	}
+	require.NoError(t, notifier.ValidateSQL(cfg))
+	sink := notifier.NewSQL("products", cfg)
+
+	first := &parser.ParseResult{RawResult: []byte(`[
+		{"sku": "a", "price": {"value": 1.5}, "stock": 3, "tags": ["x"]},
+		{"sku": "b", "price": {"value": 2}, "stock": 0},
+		{"sku": "c", "price": {"value": 3}, "stock": 7, "tags": []}
+	]`)}
+	require.NoError(t, notifier.Inform(sink, "products", first, nil, true, logger.Null, nil))
+
+	second := &parser.ParseResult{RawResult: []byte(`{"sku": "b", "price": {"value": 2.5}, "stock": 10}`)}
+	require.NoError(t, notifier.Inform(sink, "products", second, nil, false, logger.Null, nil))
+
+	db, err := sqldb.Open(config.SQLite, dsn)
+	require.NoError(t, err)
+	rows, err := db.Query(`SELECT sku, price, stock, tags FROM products ORDER BY sku`)
+	require.NoError(t, err)
+	defer rows.Close()
+	got := make([]map[string]interface{}, 0)
+	require.NoError(t, sqldb.EachRow(rows, func(row map[string]interface{}) (bool, error) {
+		got = append(got, row)
+		return true, nil
+	}))
+	body, err := json.Marshal(got)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"sku": "a", "price": 1.5, "stock": 3, "tags": "[\"x\"]"},
+		{"sku": "b", "price": 2.5, "stock": 10, "tags": null},
+		{"sku": "c", "price": 3, "stock": 7, "tags": "[]"}
+	]`, string(body))
+}
+
+func TestSQLNotifierInsertDuplicateFails(t *testing.T) {
+	cfg := &config.SQLNotifierConfig{
+		Driver:  config.SQLite,
+		DSN:     filepath.Join(t.TempDir(), "results.db"),
+		Table:   "events",
+		Columns: []*config.SQLColumn{{Name: "id", Path: "id", Type: config.Int}},
+		Key:     []string{"id"},
+	}
+	sink := notifier.NewSQL("events", cfg)
+
+	result := &parser.ParseResult{RawResult: []byte(`[{"id": 1}, {"id": 1}]`)}
+	assert.Error(t, notifier.Inform(sink, "events", result, nil, true, logger.Null, nil))
+}
+
+func TestValidateSQL(t *testing.T) {
+	columns := []*config.SQLColumn{{Name: "id"}}
+	assert.Error(t, notifier.ValidateSQL(&config.SQLNotifierConfig{Columns: columns}))
+	assert.Error(t, notifier.ValidateSQL(&config.SQLNotifierConfig{Table: "t"}))
+	assert.Error(t, notifier.ValidateSQL(&config.SQLNotifierConfig{Table: "t", Columns: columns, Mode: config.SQLUpsert}))
+	assert.Error(t, notifier.ValidateSQL(&config.SQLNotifierConfig{Table: "t", Columns: columns, Key: []string{"other"}}))
+	assert.Error(t, notifier.ValidateSQL(&config.SQLNotifierConfig{Table: "t", Columns: columns, Mode: "replace"}))
+	assert.NoError(t, notifier.ValidateSQL(&config.SQLNotifierConfig{Table: "t", Columns: columns, Key: []string{"id"}, Mode: config.SQLUpsert}))
+}
@@ -0,0 +1,64 @@
+package parser_test
+
+import (
+	"fmt"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/parser"
+	"strings"
+	"testing"
+)
+
+func arrayBenchmarkBody(size int) []byte {
+	items := make([]string, size)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"id": %d, "price": %d, "in_stock": %t}`, i, i%50, i%3 != 0)
+	}
+	return []byte("[" + strings.Join(items, ",") + "]")
+}
+
+// BenchmarkArrayExpressions evaluates an item_condition and a calculated field
+// on every element of a 5000 items array. PrepareModel compiles both programs
+// before the timer starts, so the loop measures evaluation only
+func BenchmarkArrayExpressions(b *testing.B) {
+	model := &config.Model{
+		ArrayConfig: &config.ArrayConfig{
+			RootPath:      "@this",
+			ItemCondition: "fSrc.in_stock && fRes.price > 10",
+			ItemConfig: &config.ObjectConfig{
+				Fields: map[string]*config.Field{
+					"price": {
+						BaseField: &config.BaseField{
+							Type: config.Int,
+							Path: "price",
+						},
+					},
+					"discounted": {
+						BaseField: &config.BaseField{
+							Type: config.Float,
+							Path: "price",
+							Generated: &config.GeneratedFieldConfig{
+								Calculated: &config.CalculatedConfig{
+									Type:       config.Float,
+									Expression: "fRes * 0.9",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := parser.PrepareModel(model, "model"); err != nil {
+		b.Fatal(err)
+	}
+	p := parser.NewJson(arrayBenchmarkBody(5000), logger.Null)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := p.Parse(model, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -24,6 +24,9 @@ var (
 
 type Engine interface {
 	Get(ctx context.Context, model *config.Model, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) (*ParseResult, error)
+	// Stream is Get for large results: array items are emitted one by one as
+	// soon as they are built, emit returning false stops the stream
+	Stream(ctx context.Context, model *config.Model, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable, emit func(builder.Interfacable) bool) error
 }
 
 type engine struct {
@@ -39,17 +42,36 @@ func (n *null) Get(ctx context.Context, model *config.Model, parsedValue builder
 	return nil, errInvalid
 }
 
+func (n *null) Stream(ctx context.Context, model *config.Model, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable, emit func(builder.Interfacable) bool) error {
+	return errInvalid
+}
+
 func (e *engine) Get(ctx context.Context, model *config.Model, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) (*ParseResult, error) {
 	if model == nil {
 		return nil, errMissingModelConfig
 	}
+	ctx, meta := connectors.WithResponseMeta(ctx)
 	body, err := e.connector.Get(ctx, parsedValue, index, input)
 	if err != nil {
 		e.logger.Errorw("connector return error during fetch data", "error", err.Error())
 		return nil, err
 	}
 	e.logger.Debugw("connector answer", "content", string(body))
-	return e.parser(ctx, body, e.logger).Parse(model, input)
+	return e.parser(withPageURL(ctx, meta.URL()), body, e.logger).Parse(model, input)
+}
+
+func (e *engine) Stream(ctx context.Context, model *config.Model, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable, emit func(builder.Interfacable) bool) error {
+	if model == nil {
+		return errMissingModelConfig
+	}
+	ctx, meta := connectors.WithResponseMeta(ctx)
+	body, err := e.connector.Get(ctx, parsedValue, index, input)
+	if err != nil {
+		e.logger.Errorw("connector return error during fetch data", "error", err.Error())
+		return err
+	}
+	e.logger.Debugw("connector answer", "content", string(body))
+	return e.parser(withPageURL(ctx, meta.URL()), body, e.logger).Stream(model, input, emit)
 }
 
 func NewEngine(cfg *config.ConnectorConfig, logger logger.Logger) Engine {
@@ -57,9 +79,10 @@ func NewEngine(cfg *config.ConnectorConfig, logger logger.Logger) Engine {
 		return nullEngine
 	}
 
-	var connector connectors.Connector
+	var connector, fileConnector connectors.Connector
 	if cfg.FileConfig != nil {
-		connector = connectors.NewFile(cfg.FileConfig).WithLogger(logger.With("connector", "file"))
+		fileConnector = connectors.NewFile(cfg.FileConfig).WithLogger(logger.With("connector", "file"))
+		connector = fileConnector
 	}
 	if cfg.StaticConfig != nil {
 		connector = connectors.NewStatic(cfg.StaticConfig).WithLogger(logger.With("connector", "static"))
@@ -67,6 +90,24 @@ func NewEngine(cfg *config.ConnectorConfig, logger logger.Logger) Engine {
 	if cfg.ServerConfig != nil {
 		connector = connectors.NewAPI(cfg.Url, cfg.ServerConfig, nil).WithLogger(logger.With("connector", "server"))
 	}
+	if cfg.GraphQLConfig != nil {
+		connector = connectors.NewGraphQL(cfg.Url, cfg.GraphQLConfig).WithLogger(logger.With("connector", "graphql"))
+	}
+	if cfg.SSEConfig != nil {
+		connector = connectors.NewSSE(cfg.Url, cfg.SSEConfig).WithLogger(logger.With("connector", "sse"))
+	}
+	if cfg.WebSocketConfig != nil {
+		connector = connectors.NewWebSocket(cfg.Url, cfg.WebSocketConfig).WithLogger(logger.With("connector", "websocket"))
+	}
+	if cfg.SQLConfig != nil {
+		connector = connectors.NewSQL(cfg.SQLConfig).WithLogger(logger.With("connector", "sql"))
+	}
+	if cfg.ExecConfig != nil {
+		connector = connectors.NewExec(cfg.ExecConfig).WithLogger(logger.With("connector", "exec"))
+	}
+	if cfg.SitemapConfig != nil {
+		connector = connectors.NewSitemap(cfg.Url, cfg.SitemapConfig).WithLogger(logger.With("connector", "sitemap"))
+	}
 	if cfg.BrowserConfig != nil {
 		connector = connectors.NewBrowser(cfg.Url, cfg.BrowserConfig).WithLogger(logger.With("connector", "browser"))
 	}
@@ -99,7 +140,7 @@ func NewEngine(cfg *config.ConnectorConfig, logger logger.Logger) Engine {
 		logger.Debugw("generated slice", "length", fmt.Sprintf("%d", len(genSlice)), "start", fmt.Sprintf("%d", cfg.IntSequenceConfig.Start), "end", fmt.Sprintf("%d", cfg.IntSequenceConfig.End), "step", fmt.Sprintf("%d", cfg.IntSequenceConfig.Step))
 		jsonArr := make([]builder.Interfacable, len(genSlice))
 		for i, v := range genSlice {
-			jsonArr[i] = builder.Number(float64(v))
+			jsonArr[i] = builder.Int(int64(v))
 		}
 		connector = connectors.NewStatic(&config.StaticConnectorConfig{
 			Value: builder.Array(jsonArr).ToJson(),
@@ -112,6 +153,9 @@ func NewEngine(cfg *config.ConnectorConfig, logger logger.Logger) Engine {
 	}
 	if cfg.ResponseType == config.HTML {
 		parserFactory = HTMLFactory
+		if cfg.Readability {
+			parserFactory = ReadabilityFactory
+		}
 	}
 	if cfg.ResponseType == config.XPath {
 		parserFactory = XPathFactory
@@ -122,11 +166,30 @@ func NewEngine(cfg *config.ConnectorConfig, logger logger.Logger) Engine {
 	if cfg.ResponseType == config.PDF {
 		parserFactory = PDFFactory
 	}
+	if cfg.ResponseType == config.Metadata {
+		parserFactory = MetadataFactory
+	}
+	if cfg.ResponseType == config.Feed {
+		parserFactory = FeedFactory
+	}
 
 	if connector == nil || parserFactory == nil {
 		return nullEngine
 	}
 
+	if connector == fileConnector && cfg.FileConfig.Glob != "" {
+		return &globEngine{
+			cfg:    cfg,
+			parser: parserFactory,
+			logger: logger.With("connector", "file"),
+		}
+	}
+
+	connector = connectors.WithBodyLimit(connector, cfg.MaxBodyBytes, cfg.TruncateBody)
+	connector = connectors.WithArchive(connector, cfg.Archive)
+	if cfg.ResponseType == config.HTML || cfg.ResponseType == config.XPath || cfg.ResponseType == config.XML || cfg.ResponseType == config.Metadata || cfg.ResponseType == config.Feed {
+		connector = connectors.WithCharset(connector, cfg.Charset)
+	}
 	connector = connectors.WithAttempts(connector, cfg.Attempts)
 
 	if cfg.NullOnError {
@@ -0,0 +1,505 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/tidwall/gjson"
+	"golang.org/x/net/html/charset"
+)
+
+var (
+	errUnknownFeed = errors.New("unknown feed format")
+
+	feedBOM = []byte{0xef, 0xbb, 0xbf}
+
+	feedDateLayouts = []string{
+		time.RFC3339,
+		time.RFC3339Nano,
+		time.RFC1123Z,
+		time.RFC1123,
+		"Mon, 2 Jan 2006 15:04:05 -0700",
+		"Mon, 2 Jan 2006 15:04:05 MST",
+		"Mon, 2 Jan 2006 15:04 -0700",
+		"Mon, 2 Jan 2006 15:04 MST",
+		"2 Jan 2006 15:04:05 -0700",
+		"2 Jan 2006 15:04:05 MST",
+		"Mon, 2 January 2006 15:04:05 -0700",
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+	}
+)
+
+type feedContent struct {
+	Type        string       `json:"type"`
+	Title       string       `json:"title"`
+	Link        string       `json:"link"`
+	Description string       `json:"description"`
+	Language    string       `json:"language"`
+	Updated     string       `json:"updated"`
+	Entries     []*feedEntry `json:"entries"`
+}
+
+type feedEntry struct {
+	ID         string           `json:"id"`
+	Title      string           `json:"title"`
+	Link       string           `json:"link"`
+	Published  string           `json:"published"`
+	Updated    string           `json:"updated"`
+	Author     string           `json:"author"`
+	Summary    string           `json:"summary"`
+	Content    string           `json:"content"`
+	Categories []string         `json:"categories"`
+	Enclosures []*feedEnclosure `json:"enclosures"`
+}
+
+type feedEnclosure struct {
+	URL    string `json:"url"`
+	Type   string `json:"type"`
+	Length int64  `json:"length"`
+}
+
+// xmlLink covers rss <link>text</link> and atom <link href rel type length/>
+type xmlLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+	Text   string `xml:",chardata"`
+}
+
+type xmlEnclosure struct {
+	URL      string `xml:"url,attr"`
+	Type     string `xml:"type,attr"`
+	Length   string `xml:"length,attr"`
+	FileSize string `xml:"fileSize,attr"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Links         []xmlLink `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	PubDate       string    `xml:"pubDate"`
+	Date          string    `xml:"date"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	About       string         `xml:"about,attr"`
+	GUID        string         `xml:"guid"`
+	Title       string         `xml:"title"`
+	Links       []xmlLink      `xml:"link"`
+	PubDate     string         `xml:"pubDate"`
+	Date        string         `xml:"date"`
+	Author      string         `xml:"author"`
+	Creator     string         `xml:"creator"`
+	Description string         `xml:"description"`
+	Encoded     string         `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Categories  []string       `xml:"category"`
+	Enclosures  []xmlEnclosure `xml:"enclosure"`
+	Media       []xmlEnclosure `xml:"http://search.yahoo.com/mrss/ content"`
+}
+
+// rssFeed is rss 2.0 and rss 1.0 (rdf), items of the latter are siblings of the channel
+type rssFeed struct {
+	Channel rssChannel `xml:"channel"`
+	Items   []rssItem  `xml:"item"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",innerxml"`
+}
+
+type atomPerson struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email"`
+}
+
+type atomEntry struct {
+	ID         string       `xml:"id"`
+	Title      atomText     `xml:"title"`
+	Links      []xmlLink    `xml:"link"`
+	Published  string       `xml:"published"`
+	Issued     string       `xml:"issued"`
+	Updated    string       `xml:"updated"`
+	Authors    []atomPerson `xml:"author"`
+	Summary    atomText     `xml:"summary"`
+	Content    atomText     `xml:"content"`
+	Categories []struct {
+		Term string `xml:"term,attr"`
+	} `xml:"category"`
+}
+
+type atomFeed struct {
+	Title    atomText     `xml:"title"`
+	Links    []xmlLink    `xml:"link"`
+	Subtitle atomText     `xml:"subtitle"`
+	Lang     string       `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Updated  string       `xml:"updated"`
+	Authors  []atomPerson `xml:"author"`
+	Entries  []atomEntry  `xml:"entry"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeed struct {
+	Version     string `json:"version"`
+	Title       string `json:"title"`
+	HomePageURL string `json:"home_page_url"`
+	Description string `json:"description"`
+	Language    string `json:"language"`
+	Items       []struct {
+		ID            json.RawMessage  `json:"id"`
+		URL           string           `json:"url"`
+		Title         string           `json:"title"`
+		ContentHTML   string           `json:"content_html"`
+		ContentText   string           `json:"content_text"`
+		Summary       string           `json:"summary"`
+		DatePublished string           `json:"date_published"`
+		DateModified  string           `json:"date_modified"`
+		Author        *jsonFeedAuthor  `json:"author"`
+		Authors       []jsonFeedAuthor `json:"authors"`
+		Tags          []string         `json:"tags"`
+		Attachments   []struct {
+			URL         string `json:"url"`
+			MimeType    string `json:"mime_type"`
+			SizeInBytes int64  `json:"size_in_bytes"`
+		} `json:"attachments"`
+	} `json:"items"`
+}
+
+// NewFeed normalizes RSS 2.0, RSS 1.0 (RDF), Atom and JSON Feed bodies into
+// one JSON document {"type", "title", "link", "description", "language",
+// "updated", "entries": [{"id", "title", "link", "published", "updated",
+// "author", "summary", "content", "categories", "enclosures"}]}, so the same
+// model works for every format. Dates are RFC 3339 in UTC when parsable.
+func NewFeed(body []byte, logger logger.Logger) *engineParser[*gjson.Result] {
+	content, err := parseFeed(body)
+	if err != nil {
+		logger.Errorw("unable to parse feed", "error", err.Error())
+		return NewJson(nil, logger)
+	}
+
+	jsonBody, err := json.Marshal(content)
+	if err != nil {
+		logger.Errorw("unable to marshal feed", "error", err.Error())
+		return NewJson(nil, logger)
+	}
+
+	return NewJson(jsonBody, logger)
+}
+
+func parseFeed(body []byte) (*feedContent, error) {
+	body = bytes.TrimSpace(bytes.TrimPrefix(body, feedBOM))
+	if bytes.HasPrefix(body, []byte("{")) {
+		return parseJSONFeed(body)
+	}
+
+	root, err := feedRoot(body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch root {
+	case "rss", "RDF":
+		var feed rssFeed
+		if err = decodeFeedXML(body, &feed); err != nil {
+			return nil, err
+		}
+		return normalizeRSS(&feed, root), nil
+	case "feed":
+		var feed atomFeed
+		if err = decodeFeedXML(body, &feed); err != nil {
+			return nil, err
+		}
+		return normalizeAtom(&feed), nil
+	}
+
+	return nil, fmt.Errorf("%w: <%s>", errUnknownFeed, root)
+}
+
+func newFeedDecoder(body []byte) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = charset.NewReaderLabel
+	return decoder
+}
+
+func feedRoot(body []byte) (string, error) {
+	decoder := newFeedDecoder(body)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", errUnknownFeed, err.Error())
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func decodeFeedXML(body []byte, feed interface{}) error {
+	return newFeedDecoder(body).Decode(feed)
+}
+
+func normalizeRSS(feed *rssFeed, root string) *feedContent {
+	channel := feed.Channel
+	content := &feedContent{
+		Type:        "rss",
+		Title:       feedText(channel.Title),
+		Link:        rssLink(channel.Links),
+		Description: feedText(channel.Description),
+		Language:    feedText(channel.Language),
+		Updated:     feedDate(firstNonEmpty(channel.LastBuildDate, channel.PubDate, channel.Date)),
+		Entries:     []*feedEntry{},
+	}
+	if root == "RDF" {
+		content.Type = "rdf"
+	}
+
+	for _, item := range append(channel.Items, feed.Items...) {
+		link := rssLink(item.Links)
+		entry := &feedEntry{
+			ID:         firstNonEmpty(feedText(item.GUID), item.About, link),
+			Title:      feedText(item.Title),
+			Link:       link,
+			Published:  feedDate(firstNonEmpty(item.PubDate, item.Date)),
+			Author:     rssAuthor(firstNonEmpty(item.Creator, item.Author)),
+			Summary:    feedText(item.Description),
+			Content:    feedText(firstNonEmpty(item.Encoded, item.Description)),
+			Categories: []string{},
+			Enclosures: []*feedEnclosure{},
+		}
+		entry.Updated = entry.Published
+		for _, category := range item.Categories {
+			if category = feedText(category); category != "" {
+				entry.Categories = append(entry.Categories, category)
+			}
+		}
+		for _, enclosure := range append(item.Enclosures, item.Media...) {
+			if enclosure.URL == "" {
+				continue
+			}
+			entry.Enclosures = append(entry.Enclosures, &feedEnclosure{
+				URL:    strings.TrimSpace(enclosure.URL),
+				Type:   enclosure.Type,
+				Length: feedLength(firstNonEmpty(enclosure.Length, enclosure.FileSize)),
+			})
+		}
+		content.Entries = append(content.Entries, entry)
+	}
+
+	return content
+}
+
+// rssLink skips <atom:link rel="self" href/> of the channel
+func rssLink(links []xmlLink) string {
+	for _, link := range links {
+		if text := feedText(link.Text); text != "" {
+			return text
+		}
+	}
+	for _, link := range links {
+		if link.Href != "" && (link.Rel == "" || link.Rel == "alternate") {
+			return strings.TrimSpace(link.Href)
+		}
+	}
+	return ""
+}
+
+// rssAuthor turns "jane@example.com (Jane Doe)" into the name
+func rssAuthor(author string) string {
+	author = feedText(author)
+	if open, close := strings.Index(author, "("), strings.LastIndex(author, ")"); open >= 0 && close > open && strings.Contains(author[:open], "@") {
+		return strings.TrimSpace(author[open+1 : close])
+	}
+	return author
+}
+
+func normalizeAtom(feed *atomFeed) *feedContent {
+	content := &feedContent{
+		Type:        "atom",
+		Title:       atomValue(feed.Title),
+		Link:        atomLink(feed.Links, "alternate"),
+		Description: atomValue(feed.Subtitle),
+		Language:    feed.Lang,
+		Updated:     feedDate(feed.Updated),
+		Entries:     []*feedEntry{},
+	}
+
+	for _, item := range feed.Entries {
+		authors := item.Authors
+		if len(authors) == 0 {
+			authors = feed.Authors
+		}
+		entry := &feedEntry{
+			ID:         feedText(item.ID),
+			Title:      atomValue(item.Title),
+			Link:       atomLink(item.Links, "alternate"),
+			Published:  feedDate(firstNonEmpty(item.Published, item.Issued, item.Updated)),
+			Updated:    feedDate(firstNonEmpty(item.Updated, item.Published)),
+			Summary:    atomValue(item.Summary),
+			Content:    atomValue(item.Content),
+			Categories: []string{},
+			Enclosures: []*feedEnclosure{},
+		}
+		if len(authors) > 0 {
+			entry.Author = feedText(firstNonEmpty(authors[0].Name, authors[0].Email))
+		}
+		if entry.Content == "" {
+			entry.Content = entry.Summary
+		}
+		if entry.ID == "" {
+			entry.ID = entry.Link
+		}
+		for _, category := range item.Categories {
+			if category.Term != "" {
+				entry.Categories = append(entry.Categories, category.Term)
+			}
+		}
+		for _, link := range item.Links {
+			if link.Rel == "enclosure" && link.Href != "" {
+				entry.Enclosures = append(entry.Enclosures, &feedEnclosure{
+					URL:    strings.TrimSpace(link.Href),
+					Type:   link.Type,
+					Length: feedLength(link.Length),
+				})
+			}
+		}
+		content.Entries = append(content.Entries, entry)
+	}
+
+	return content
+}
+
+func atomLink(links []xmlLink, rel string) string {
+	for _, link := range links {
+		if link.Rel == rel || (link.Rel == "" && rel == "alternate") {
+			return strings.TrimSpace(link.Href)
+		}
+	}
+	return ""
+}
+
+// atomValue unescapes text constructs, xhtml content is kept as markup
+func atomValue(text atomText) string {
+	if text.Type == "xhtml" {
+		return strings.TrimSpace(text.Body)
+	}
+
+	var value struct {
+		Text string `xml:",chardata"`
+	}
+	if err := xml.Unmarshal([]byte("<v>"+text.Body+"</v>"), &value); err != nil {
+		return strings.TrimSpace(text.Body)
+	}
+	return strings.TrimSpace(value.Text)
+}
+
+func parseJSONFeed(body []byte) (*feedContent, error) {
+	var feed jsonFeed
+	if err := json.Unmarshal(body, &feed); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(feed.Version, "https://jsonfeed.org/version/") {
+		return nil, fmt.Errorf("%w: json without jsonfeed version", errUnknownFeed)
+	}
+
+	content := &feedContent{
+		Type:        "json",
+		Title:       feed.Title,
+		Link:        feed.HomePageURL,
+		Description: feed.Description,
+		Language:    feed.Language,
+		Entries:     []*feedEntry{},
+	}
+
+	for _, item := range feed.Items {
+		entry := &feedEntry{
+			ID:         jsonFeedID(item.ID),
+			Title:      item.Title,
+			Link:       item.URL,
+			Published:  feedDate(firstNonEmpty(item.DatePublished, item.DateModified)),
+			Updated:    feedDate(firstNonEmpty(item.DateModified, item.DatePublished)),
+			Summary:    item.Summary,
+			Content:    firstNonEmpty(item.ContentHTML, item.ContentText, item.Summary),
+			Categories: []string{},
+			Enclosures: []*feedEnclosure{},
+		}
+		if entry.ID == "" {
+			entry.ID = entry.Link
+		}
+		if len(item.Authors) > 0 {
+			entry.Author = item.Authors[0].Name
+		} else if item.Author != nil {
+			entry.Author = item.Author.Name
+		}
+		entry.Categories = append(entry.Categories, item.Tags...)
+		for _, attachment := range item.Attachments {
+			entry.Enclosures = append(entry.Enclosures, &feedEnclosure{
+				URL:    attachment.URL,
+				Type:   attachment.MimeType,
+				Length: attachment.SizeInBytes,
+			})
+		}
+		content.Entries = append(content.Entries, entry)
+	}
+
+	return content, nil
+}
+
+// jsonFeedID accepts numeric ids of sloppy feeds
+func jsonFeedID(raw json.RawMessage) string {
+	var id string
+	if json.Unmarshal(raw, &id) == nil {
+		return id
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+// feedDate converts the date to RFC 3339 in UTC, unknown formats are kept as is
+func feedDate(value string) string {
+	value = feedText(value)
+	if value == "" {
+		return ""
+	}
+
+	for _, layout := range feedDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date.UTC().Format(time.RFC3339)
+		}
+	}
+	return value
+}
+
+func feedLength(value string) int64 {
+	length, _ := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	return length
+}
+
+func feedText(value string) string {
+	return strings.TrimSpace(value)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
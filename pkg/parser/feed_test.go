@@ -0,0 +1,178 @@
+package parser_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	rssFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom" xmlns:content="http://purl.org/rss/1.0/modules/content/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<channel>
+	<title>Go Blog</title>
+	<atom:link href="https://example.com/feed.xml" rel="self" type="application/rss+xml"/>
+	<link>https://example.com/</link>
+	<description>News</description>
+	<lastBuildDate>Wed, 01 May 2024 10:00:00 +0200</lastBuildDate>
+	<item>
+		<title>Go 1.22</title>
+		<link>https://example.com/go1.22</link>
+		<guid isPermaLink="false">post-1</guid>
+		<pubDate>Wed, 01 May 2024 10:00:00 +0200</pubDate>
+		<author>jane@example.com (Jane Doe)</author>
+		<category>go</category>
+		<description><![CDATA[<p>Short</p>]]></description>
+		<content:encoded><![CDATA[<p>Full text</p>]]></content:encoded>
+		<enclosure url="https://example.com/talk.mp3" type="audio/mpeg" length="1024"/>
+	</item>
+</channel>
+</rss>`
+
+	atomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xml:lang="en">
+	<title>Go Blog</title>
+	<link href="https://example.com/feed.atom" rel="self"/>
+	<link href="https://example.com/"/>
+	<subtitle>News</subtitle>
+	<updated>2024-05-01T08:00:00Z</updated>
+	<author><name>Jane Doe</name></author>
+	<entry>
+		<id>post-1</id>
+		<title type="html">Go 1.22 &amp;lt;3</title>
+		<link rel="alternate" href="https://example.com/go1.22"/>
+		<link rel="enclosure" href="https://example.com/talk.mp3" type="audio/mpeg" length="1024"/>
+		<published>2024-05-01T10:00:00+02:00</published>
+		<updated>2024-05-02T08:00:00Z</updated>
+		<category term="go"/>
+		<summary>Short</summary>
+		<content type="html">&lt;p&gt;Full text&lt;/p&gt;</content>
+	</entry>
+</feed>`
+
+	jsonFeed = `{
+		"version": "https://jsonfeed.org/version/1.1",
+		"title": "Go Blog",
+		"home_page_url": "https://example.com/",
+		"description": "News",
+		"items": [{
+			"id": "post-1",
+			"url": "https://example.com/go1.22",
+			"title": "Go 1.22",
+			"content_html": "<p>Full text</p>",
+			"summary": "Short",
+			"date_published": "2024-05-01T10:00:00+02:00",
+			"authors": [{"name": "Jane Doe"}],
+			"tags": ["go"],
+			"attachments": [{"url": "https://example.com/talk.mp3", "mime_type": "audio/mpeg", "size_in_bytes": 1024}]
+		}]
+	}`
+
+	rdfFeed = `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+	<channel rdf:about="https://example.com/">
+		<title>Go Blog</title>
+		<link>https://example.com/</link>
+		<description>News</description>
+	</channel>
+	<item rdf:about="https://example.com/go1.22">
+		<title>Go 1.22</title>
+		<link>https://example.com/go1.22</link>
+		<dc:date>2024-05-01T08:00:00Z</dc:date>
+		<dc:creator>Jane Doe</dc:creator>
+		<description>Short</description>
+	</item>
+</rdf:RDF>`
+)
+
+func feedModel() *config.Model {
+	field := func(fieldType config.FieldType, path string) *config.Field {
+		return &config.Field{BaseField: &config.BaseField{Type: fieldType, Path: path}}
+	}
+
+	return &config.Model{
+		ObjectConfig: &config.ObjectConfig{
+			Fields: map[string]*config.Field{
+				"type":  field(config.String, "type"),
+				"title": field(config.String, "title"),
+				"link":  field(config.String, "link"),
+				"entries": {ArrayConfig: &config.ArrayConfig{
+					RootPath: "entries",
+					ItemConfig: &config.ObjectConfig{
+						Fields: map[string]*config.Field{
+							"title":     field(config.RawString, "title"),
+							"link":      field(config.String, "link"),
+							"published": field(config.String, "published"),
+							"author":    field(config.String, "author"),
+							"summary":   field(config.RawString, "summary"),
+							"content":   field(config.RawString, "content"),
+							"enclosure": field(config.String, "enclosures.0.url"),
+						},
+					},
+				}},
+			},
+		},
+	}
+}
+
+func TestFeedParser(t *testing.T) {
+	for name, tc := range map[string]struct {
+		body     string
+		expected string
+	}{
+		"rss": {body: rssFeed, expected: `{"type": "rss", "title": "Go Blog", "link": "https://example.com/", "entries": [{
+			"title": "Go 1.22", "link": "https://example.com/go1.22", "published": "2024-05-01T08:00:00Z", "author": "Jane Doe",
+			"summary": "<p>Short</p>", "content": "<p>Full text</p>", "enclosure": "https://example.com/talk.mp3"}]}`},
+		"atom": {body: atomFeed, expected: `{"type": "atom", "title": "Go Blog", "link": "https://example.com/", "entries": [{
+			"title": "Go 1.22 &lt;3", "link": "https://example.com/go1.22", "published": "2024-05-01T08:00:00Z", "author": "Jane Doe",
+			"summary": "Short", "content": "<p>Full text</p>", "enclosure": "https://example.com/talk.mp3"}]}`},
+		"json": {body: jsonFeed, expected: `{"type": "json", "title": "Go Blog", "link": "https://example.com/", "entries": [{
+			"title": "Go 1.22", "link": "https://example.com/go1.22", "published": "2024-05-01T08:00:00Z", "author": "Jane Doe",
+			"summary": "Short", "content": "<p>Full text</p>", "enclosure": "https://example.com/talk.mp3"}]}`},
+		"rdf": {body: rdfFeed, expected: `{"type": "rdf", "title": "Go Blog", "link": "https://example.com/", "entries": [{
+			"title": "Go 1.22", "link": "https://example.com/go1.22", "published": "2024-05-01T08:00:00Z", "author": "Jane Doe",
+			"summary": "Short", "content": "Short", "enclosure": ""}]}`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			res, err := parser.NewFeed([]byte(tc.body), logger.Null).Parse(feedModel(), nil)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(res.Raw()))
+		})
+	}
+}
+
+func TestFeedParserInvalid(t *testing.T) {
+	res, err := parser.NewFeed([]byte(`<html><body>not a feed</body></html>`), logger.Null).Parse(feedModel(), nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "", "title": "", "link": "", "entries": []}`, string(res.Raw()))
+}
+
+func TestFeedParserJSONCharset(t *testing.T) {
+	body := `{"version": "https://jsonfeed.org/version/1.1", "description": "` + strings.Repeat("a", 2048) + `", "items": [{"title": "Café"}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/feed+json; charset=iso-8859-1")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	res, err := parser.NewEngine(&config.ConnectorConfig{
+		ResponseType: config.Feed,
+		Url:          server.URL,
+		ServerConfig: &config.ServerConnectorConfig{
+			Method: http.MethodGet,
+		},
+	}, logger.Null).Get(context.Background(), &config.Model{
+		BaseField: &config.BaseField{Type: config.String, Path: "entries.0.title"},
+	}, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Café", gjson.ParseBytes(res.Raw()).String())
+}
@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"context"
+	"time"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/connectors"
+	"github.com/PxyUp/fitter/pkg/logger"
+)
+
+var (
+	_ Engine = &globEngine{}
+)
+
+// globEngine parses every file matched by the file connector glob with the
+// model and collects the results with the file metadata into an array
+type globEngine struct {
+	cfg    *config.ConnectorConfig
+	parser Factory
+	logger logger.Logger
+}
+
+// listError mirrors the single file connector: with null_on_error the model
+// is parsed from null, otherwise the error is returned
+func (g *globEngine) listError(ctx context.Context, err error) (Parser, error) {
+	g.logger.Errorw("unable to list files", "path", g.cfg.FileConfig.Path, "glob", g.cfg.FileConfig.Glob, "error", err.Error())
+	if !g.cfg.NullOnError {
+		return nil, err
+	}
+	return g.parser(ctx, builder.NullValue.Raw(), g.logger), nil
+}
+
+func (g *globEngine) Get(ctx context.Context, model *config.Model, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) (*ParseResult, error) {
+	if model == nil {
+		return nil, errMissingModelConfig
+	}
+
+	files, err := connectors.MatchFiles(g.cfg.FileConfig, parsedValue, index, input)
+	if err != nil {
+		nullParser, errList := g.listError(ctx, err)
+		if errList != nil {
+			return nil, errList
+		}
+		return nullParser.Parse(model, input)
+	}
+
+	values := make([]builder.Interfacable, len(files))
+	errs := make([]error, len(files))
+	resolveConcurrently(len(files), func(i int) {
+		values[i], errs[i] = g.parseFile(ctx, files[i], model, parsedValue, index, input)
+	})
+	for _, errFile := range errs {
+		if errFile != nil {
+			return nil, errFile
+		}
+	}
+
+	res := builder.Array(values)
+	return &ParseResult{
+		RawResult: res.Raw(),
+		Json:      res.ToJson(),
+	}, nil
+}
+
+func (g *globEngine) Stream(ctx context.Context, model *config.Model, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable, emit func(builder.Interfacable) bool) error {
+	if model == nil {
+		return errMissingModelConfig
+	}
+
+	files, err := connectors.MatchFiles(g.cfg.FileConfig, parsedValue, index, input)
+	if err != nil {
+		nullParser, errList := g.listError(ctx, err)
+		if errList != nil {
+			return errList
+		}
+		return nullParser.Stream(model, input, emit)
+	}
+
+	for _, file := range files {
+		if errCtx := ctx.Err(); errCtx != nil {
+			return errCtx
+		}
+		value, errFile := g.parseFile(ctx, file, model, parsedValue, index, input)
+		if errFile != nil {
+			return errFile
+		}
+		if !emit(value) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// parseFile runs the single file engine, so null_on_error, attempts and body
+// limits of the connector apply to every matched file
+func (g *globEngine) parseFile(ctx context.Context, file *connectors.FileMatch, model *config.Model, parsedValue builder.Interfacable, index *uint32, input builder.Interfacable) (builder.Interfacable, error) {
+	fileCfg := *g.cfg
+	fileCfg.FileConfig = &config.FileConnectorConfig{
+		Path:          file.Path,
+		UseFormatting: g.cfg.FileConfig.UseFormatting,
+	}
+
+	res, err := NewEngine(&fileCfg, g.logger.With("file", file.Path)).Get(ctx, model, parsedValue, index, input)
+	if err != nil {
+		g.logger.Errorw("unable to parse file", "path", file.Path, "error", err.Error())
+		return nil, err
+	}
+
+	return builder.Object(map[string]builder.Interfacable{
+		"file_name": builder.String(file.Name, false),
+		"path":      builder.String(file.Path, false),
+		"mod_time":  builder.String(file.ModTime.UTC().Format(time.RFC3339), false),
+		"result":    builder.ToJsonable(res.Raw()),
+	}), nil
+}
@@ -0,0 +1,144 @@
+package parser_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func writeGlobFiles(t *testing.T) string {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "nested"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"id": 1}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.json"), []byte(`{"id": 2}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "skip.txt"), []byte(`{"id": 3}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "nested", "c.json"), []byte(`{"id": 4}`), 0o644))
+	return dir
+}
+
+func globModel() *config.Model {
+	return &config.Model{
+		ObjectConfig: &config.ObjectConfig{
+			Fields: map[string]*config.Field{
+				"id": {
+					BaseField: &config.BaseField{
+						Type: config.Int,
+						Path: "id",
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGlobEngine(t *testing.T) {
+	dir := writeGlobFiles(t)
+
+	for name, tc := range map[string]struct {
+		recursive bool
+		names     []string
+		ids       []int64
+	}{
+		"flat":      {recursive: false, names: []string{"a.json", "b.json"}, ids: []int64{1, 2}},
+		"recursive": {recursive: true, names: []string{"a.json", "b.json", "c.json"}, ids: []int64{1, 2, 4}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			engine := parser.NewEngine(&config.ConnectorConfig{
+				ResponseType: config.Json,
+				FileConfig: &config.FileConnectorConfig{
+					Path:      dir,
+					Glob:      "*.json",
+					Recursive: tc.recursive,
+				},
+			}, logger.Null)
+
+			res, err := engine.Get(context.Background(), globModel(), nil, nil, nil)
+			require.NoError(t, err)
+
+			items := gjson.Parse(res.ToJson()).Array()
+			require.Len(t, items, len(tc.names))
+			for i, item := range items {
+				assert.Equal(t, tc.names[i], item.Get("file_name").String())
+				assert.Equal(t, tc.ids[i], item.Get("result.id").Int())
+				assert.True(t, filepath.IsAbs(item.Get("path").String()))
+				assert.NotEmpty(t, item.Get("mod_time").String())
+			}
+		})
+	}
+}
+
+func TestGlobEngine_Stream(t *testing.T) {
+	dir := writeGlobFiles(t)
+
+	engine := parser.NewEngine(&config.ConnectorConfig{
+		ResponseType: config.Json,
+		FileConfig: &config.FileConnectorConfig{
+			Path:      dir,
+			Glob:      "nested/*.json",
+			Recursive: true,
+		},
+	}, logger.Null)
+
+	var res []string
+	err := engine.Stream(context.Background(), globModel(), nil, nil, nil, func(value builder.Interfacable) bool {
+		res = append(res, value.ToJson())
+		return true
+	})
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, "c.json", gjson.Get(res[0], "file_name").String())
+	assert.Equal(t, int64(4), gjson.Get(res[0], "result.id").Int())
+}
+
+func TestGlobEngine_Errors(t *testing.T) {
+	dir := writeGlobFiles(t)
+
+	for name, tc := range map[string]struct {
+		path        string
+		maxBody     int64
+		nullOnError bool
+		expected    string
+	}{
+		"missing directory":               {path: filepath.Join(dir, "missing")},
+		"missing directory null on error": {path: filepath.Join(dir, "missing"), nullOnError: true, expected: `{"id": null}`},
+		"file error":                      {path: dir, maxBody: 4},
+		"file error null on error": {path: dir, maxBody: 4, nullOnError: true, expected: `[
+			{"file_name": "a.json", "result": {"id": null}},
+			{"file_name": "b.json", "result": {"id": null}}
+		]`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			engine := parser.NewEngine(&config.ConnectorConfig{
+				ResponseType: config.Json,
+				MaxBodyBytes: tc.maxBody,
+				NullOnError:  tc.nullOnError,
+				FileConfig: &config.FileConnectorConfig{
+					Path: tc.path,
+					Glob: "*.json",
+				},
+			}, logger.Null)
+
+			res, err := engine.Get(context.Background(), globModel(), nil, nil, nil)
+			if tc.expected == "" {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			actual := res.ToJson()
+			if gjson.Parse(actual).IsArray() {
+				actual = gjson.Get(actual, "#.{file_name,result}").Raw
+			}
+			assert.JSONEq(t, tc.expected, actual)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/PxyUp/fitter/pkg/builder"
 	"github.com/PxyUp/fitter/pkg/config"
 	"github.com/PxyUp/fitter/pkg/logger"
+	"golang.org/x/net/html"
 	"strconv"
 )
 
@@ -19,32 +20,40 @@ func selectionToArray(parent *goquery.Selection) []*goquery.Selection {
 	return tmp
 }
 
-func htmlFillUpBaseField(source *goquery.Selection, field *config.BaseField) builder.Interfacable {
+func htmlFillUpBaseField(source *goquery.Selection, field *config.BaseField, base urlBase) builder.Interfacable {
+	convert := func(text string) builder.Interfacable {
+		return htmlTextValue(text, field, base)
+	}
+
 	if source.Length() <= 0 {
-		return builder.NullValue
+		return missingValue(field, convert)
 	}
 
-	if field.Type == config.HtmlString {
+	var text string
+
+	if field.Type == config.HtmlString || field.Type == config.Markdown {
 		htmlString, err := source.Html()
 		if err != nil {
 			return builder.NullValue
 		}
-		return builder.String(htmlString)
-	}
-
-	var text string
-
-	if field.HTMLAttribute != "" {
+		text = htmlString
+	} else if field.HTMLAttribute != "" {
 		attrValue, attrExists := source.First().Attr(field.HTMLAttribute)
 		if !attrExists {
-			return builder.NullValue
+			return missingValue(field, convert)
 		}
 		text = attrValue
 	} else {
 		text = source.First().Text()
 	}
 
+	return baseFieldValue(text, field, convert)
+}
+
+func htmlTextValue(text string, field *config.BaseField, base urlBase) builder.Interfacable {
 	switch field.Type {
+	case config.HtmlString:
+		return builder.String(text)
 	case config.Null:
 		return builder.NullValue
 	case config.RawString:
@@ -58,11 +67,13 @@ func htmlFillUpBaseField(source *goquery.Selection, field *config.BaseField) bui
 		}
 		return builder.Bool(boolValue)
 	case config.Float, config.Float64, config.Int, config.Int64:
-		float32Value, err := strconv.ParseFloat(text, 64)
-		if err != nil {
-			return builder.NullValue
-		}
-		return builder.Number(float32Value)
+		return numberValue(text, field)
+	case config.Money:
+		return moneyValue(text, field)
+	case config.URL:
+		return urlValue(text, field, base)
+	case config.Markdown:
+		return builder.String(htmlToMarkdown(text, base), false)
 	case config.Array, config.Object:
 		return builder.ToJsonableFromString(text)
 	}
@@ -72,6 +83,7 @@ func htmlFillUpBaseField(source *goquery.Selection, field *config.BaseField) bui
 
 func NewHTML(body []byte, logger logger.Logger) *engineParser[*goquery.Selection] {
 	document, _ := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	baseHref, _ := document.Find("base[href]").First().Attr("href")
 
 	return &engineParser[*goquery.Selection]{
 		getText: func(r *goquery.Selection) string {
@@ -94,5 +106,15 @@ func NewHTML(body []byte, logger logger.Logger) *engineParser[*goquery.Selection
 			return parent.Find(path)
 		},
 		customFillUpBaseField: htmlFillUpBaseField,
+		baseHref:              baseHref,
+		getTable: func(parent *goquery.Selection, path string) *html.Node {
+			if path != "" {
+				parent = parent.Find(path)
+			}
+			if parent.Length() == 0 {
+				return nil
+			}
+			return parent.Nodes[0]
+		},
 	}
 }
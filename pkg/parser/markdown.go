@@ -0,0 +1,320 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PxyUp/fitter/pkg/config"
+	"golang.org/x/net/html"
+)
+
+var (
+	markdownSpaces     = regexp.MustCompile(`[ \t]+`)
+	markdownWhitespace = regexp.MustCompile(`\s+`)
+	markdownNewLines   = regexp.MustCompile(`\n{3,}`)
+
+	// markdownContainers hold blocks, their inline content becomes paragraphs
+	markdownContainers = map[string]bool{
+		"html": true, "body": true, "div": true, "section": true, "article": true, "main": true, "header": true,
+		"footer": true, "aside": true, "nav": true, "figure": true, "form": true, "fieldset": true, "details": true,
+		"dl": true, "address": true, "center": true,
+	}
+	markdownParagraphs = map[string]bool{
+		"p": true, "figcaption": true, "dt": true, "dd": true, "summary": true, "caption": true, "legend": true,
+	}
+	markdownSkipped = map[string]bool{
+		"script": true, "style": true, "noscript": true, "template": true, "head": true, "svg": true,
+		"button": true, "input": true, "select": true, "textarea": true, "iframe": true,
+	}
+)
+
+// markdownConverter renders html nodes as markdown, links and images are
+// resolved against the base when it is known
+type markdownConverter struct {
+	base urlBase
+}
+
+// htmlToMarkdown converts the html fragment
+func htmlToMarkdown(fragment string, base urlBase) string {
+	document, err := html.Parse(strings.NewReader(fragment))
+	if err != nil {
+		return ""
+	}
+	return (&markdownConverter{base: base}).convert(document)
+}
+
+func (c *markdownConverter) convert(node *html.Node) string {
+	markdown := strings.Join(c.blocks(node), "\n\n")
+	return strings.TrimSpace(markdownNewLines.ReplaceAllString(markdown, "\n\n"))
+}
+
+// blocks renders the children of the container, inline children are joined
+// into paragraphs
+func (c *markdownConverter) blocks(node *html.Node) []string {
+	var blocks []string
+	var paragraph strings.Builder
+
+	flush := func() {
+		if text := cleanInline(paragraph.String()); text != "" {
+			blocks = append(blocks, text)
+		}
+		paragraph.Reset()
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode || !isMarkdownBlock(child.Data) {
+			paragraph.WriteString(c.inline(child))
+			continue
+		}
+
+		flush()
+		if block := c.block(child); len(block) > 0 {
+			blocks = append(blocks, block...)
+		}
+	}
+	flush()
+
+	return blocks
+}
+
+func isMarkdownBlock(tag string) bool {
+	switch tag {
+	case "h1", "h2", "h3", "h4", "h5", "h6", "ul", "ol", "pre", "blockquote", "table", "hr":
+		return true
+	}
+	return markdownContainers[tag] || markdownParagraphs[tag] || markdownSkipped[tag]
+}
+
+func (c *markdownConverter) block(node *html.Node) []string {
+	switch tag := node.Data; {
+	case markdownSkipped[tag]:
+		return nil
+	case markdownContainers[tag]:
+		return c.blocks(node)
+	case markdownParagraphs[tag]:
+		if text := cleanInline(c.children(node)); text != "" {
+			return []string{text}
+		}
+		return nil
+	case len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6':
+		text := strings.ReplaceAll(cleanInline(c.children(node)), "\n", " ")
+		if text == "" {
+			return nil
+		}
+		return []string{strings.Repeat("#", int(tag[1]-'0')) + " " + text}
+	case tag == "ul" || tag == "ol":
+		if list := c.list(node, ""); list != "" {
+			return []string{list}
+		}
+		return nil
+	case tag == "pre":
+		return []string{c.code(node)}
+	case tag == "blockquote":
+		inner := strings.Join(c.blocks(node), "\n\n")
+		if inner == "" {
+			return nil
+		}
+		lines := strings.Split(inner, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return []string{strings.Join(lines, "\n")}
+	case tag == "table":
+		if table := c.table(node); table != "" {
+			return []string{table}
+		}
+		return nil
+	case tag == "hr":
+		return []string{"---"}
+	}
+
+	return nil
+}
+
+func (c *markdownConverter) children(node *html.Node) string {
+	var text strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		text.WriteString(c.inline(child))
+	}
+	return text.String()
+}
+
+func (c *markdownConverter) inline(node *html.Node) string {
+	switch node.Type {
+	case html.TextNode:
+		return markdownWhitespace.ReplaceAllString(node.Data, " ")
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch node.Data {
+	case "br":
+		return "\\\n"
+	case "a":
+		text := c.children(node)
+		href := strings.TrimSpace(attr(node, "href"))
+		if href == "" || strings.HasPrefix(strings.ToLower(href), "javascript:") || strings.TrimSpace(text) == "" {
+			return text
+		}
+		return wrapInline(text, "[", "]("+c.resolve(href)+")")
+	case "img":
+		src := strings.TrimSpace(attr(node, "src"))
+		if src == "" {
+			return ""
+		}
+		return "![" + attr(node, "alt") + "](" + c.resolve(src) + ")"
+	case "strong", "b":
+		return wrapInline(c.children(node), "**", "**")
+	case "em", "i":
+		return wrapInline(c.children(node), "*", "*")
+	case "del", "s", "strike":
+		return wrapInline(c.children(node), "~~", "~~")
+	case "code", "kbd", "samp":
+		return wrapInline(textContent(node), "`", "`")
+	}
+
+	if markdownSkipped[node.Data] {
+		return ""
+	}
+	if isMarkdownBlock(node.Data) {
+		// block inside inline content (<a><div>...</div></a>)
+		return " " + c.children(node) + " "
+	}
+	return c.children(node)
+}
+
+// wrapInline puts the markers around the trimmed text, keeping the spaces
+// outside: "<b> bold </b>" -> " **bold** "
+func wrapInline(text string, open string, close string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+
+	var prefix, suffix string
+	if strings.TrimLeft(text, " \t\n") != text {
+		prefix = " "
+	}
+	if strings.TrimRight(text, " \t\n") != text {
+		suffix = " "
+	}
+	return prefix + open + trimmed + close + suffix
+}
+
+// cleanInline collapses the spaces of the paragraph and trims every line
+func cleanInline(text string) string {
+	lines := strings.Split(markdownSpaces.ReplaceAllString(text, " "), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSuffix(strings.TrimSpace(strings.Join(lines, "\n")), "\\")
+}
+
+func (c *markdownConverter) list(node *html.Node, indent string) string {
+	ordered := node.Data == "ol"
+	number := 1
+	if start, err := strconv.Atoi(attr(node, "start")); err == nil {
+		number = start
+	}
+
+	var lines []string
+	for item := node.FirstChild; item != nil; item = item.NextSibling {
+		if item.Type != html.ElementNode || item.Data != "li" {
+			continue
+		}
+
+		marker := "- "
+		if ordered {
+			marker = fmt.Sprintf("%d. ", number)
+			number++
+		}
+
+		var text strings.Builder
+		var nested []string
+		for child := item.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && (child.Data == "ul" || child.Data == "ol") {
+				if list := c.list(child, indent+strings.Repeat(" ", len(marker))); list != "" {
+					nested = append(nested, list)
+				}
+				continue
+			}
+			text.WriteString(c.inline(child))
+		}
+
+		lines = append(lines, indent+marker+strings.ReplaceAll(cleanInline(text.String()), "\n", " "))
+		lines = append(lines, nested...)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (c *markdownConverter) code(node *html.Node) string {
+	language := ""
+	for _, current := range []*html.Node{node, node.FirstChild} {
+		if current == nil || current.Type != html.ElementNode {
+			continue
+		}
+		for _, class := range strings.Fields(attr(current, "class")) {
+			if strings.HasPrefix(class, "language-") {
+				language = strings.TrimPrefix(class, "language-")
+			}
+		}
+	}
+
+	return "```" + language + "\n" + strings.Trim(textContent(node), "\n") + "\n```"
+}
+
+// table renders the gfm table, the first row is the header
+func (c *markdownConverter) table(node *html.Node) string {
+	grid, _ := tableGrid(node, func(cell *html.Node) string {
+		text := strings.ReplaceAll(cleanInline(c.children(cell)), "\\\n", " ")
+		return strings.ReplaceAll(strings.ReplaceAll(text, "\n", " "), "|", "\\|")
+	})
+	if len(grid) == 0 {
+		return ""
+	}
+
+	width := 0
+	for _, cells := range grid {
+		width = max(width, len(cells))
+	}
+
+	row := func(cells []*tableCell) string {
+		values := make([]string, width)
+		for i := range values {
+			if i < len(cells) && cells[i] != nil {
+				values[i] = cells[i].text
+			}
+		}
+		return "| " + strings.Join(values, " | ") + " |"
+	}
+
+	lines := []string{row(grid[0]), "|" + strings.Repeat(" --- |", width)}
+	for _, cells := range grid[1:] {
+		lines = append(lines, row(cells))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (c *markdownConverter) resolve(link string) string {
+	if value, ok := urlValue(link, &config.BaseField{}, c.base).ToInterface().(string); ok {
+		return value
+	}
+	return link
+}
+
+// textContent is the raw text of the node, whitespace is kept
+func textContent(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+
+	var text strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		text.WriteString(textContent(child))
+	}
+	return text.String()
+}
@@ -0,0 +1,84 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestMarkdownField(t *testing.T) {
+	body := []byte(`<html><head><base href="https://example.com/blog/"></head><body><article class="post">
+		<h2>Hello  <em>world</em></h2>
+		<p>Some <b>bold</b> text with a <a href="/about">link</a> and <code>x := 1</code>.<br>Next line</p>
+		<ul>
+			<li>One</li>
+			<li>Two
+				<ol><li>Nested</li></ol>
+			</li>
+		</ul>
+		<blockquote><p>Quote</p></blockquote>
+		<pre><code class="language-go">fmt.Println("hi")
+</code></pre>
+		<table>
+			<tr><th>Name</th><th>Price</th></tr>
+			<tr><td><a href="apple">Apple</a></td><td>1|2</td></tr>
+		</table>
+		<img src="cat.png" alt="Cat">
+		<script>alert(1)</script>
+	</article></body></html>`)
+
+	res, err := parser.NewHTML(body, logger.Null).Parse(&config.Model{
+		BaseField: &config.BaseField{
+			Type: config.Markdown,
+			Path: "article",
+		},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "## Hello *world*\n\n"+
+		"Some **bold** text with a [link](https://example.com/about) and `x := 1`.\\\nNext line\n\n"+
+		"- One\n- Two\n  1. Nested\n\n"+
+		"> Quote\n\n"+
+		"```go\nfmt.Println(\"hi\")\n```\n\n"+
+		"| Name | Price |\n| --- | --- |\n| [Apple](https://example.com/blog/apple) | 1\\|2 |\n\n"+
+		"![Cat](https://example.com/blog/cat.png)", gjson.ParseBytes(res.Raw()).String())
+}
+
+func TestMarkdownFieldOtherParsers(t *testing.T) {
+	for name, tc := range map[string]struct {
+		parser parser.Parser
+		path   string
+	}{
+		"json": {
+			parser: parser.NewJson([]byte(`{"body_html": "<p>Some <b>bold</b> <a href=\"https://example.com/about\">link</a></p>"}`), logger.Null),
+			path:   "body_html",
+		},
+		"xpath": {
+			parser: parser.NewXPath([]byte(`<html><body><div id="post"><p>Some <b>bold</b> <a href="https://example.com/about">link</a></p></div></body></html>`), logger.Null),
+			path:   "//div[@id='post']",
+		},
+		"xml": {
+			parser: parser.NewXML([]byte(`<post><body><![CDATA[<p>Some <b>bold</b> <a href="https://example.com/about">link</a></p>]]></body></post>`), logger.Null),
+			path:   "//body",
+		},
+		"feed": {
+			parser: parser.NewFeed([]byte(`<rss version="2.0"><channel><item><description><![CDATA[<p>Some <b>bold</b> <a href="https://example.com/about">link</a></p>]]></description></item></channel></rss>`), logger.Null),
+			path:   "entries.0.summary",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			res, err := tc.parser.Parse(&config.Model{
+				BaseField: &config.BaseField{
+					Type: config.Markdown,
+					Path: tc.path,
+				},
+			}, nil)
+			require.NoError(t, err)
+			assert.Equal(t, "Some **bold** [link](https://example.com/about)", gjson.ParseBytes(res.Raw()).String())
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/tidwall/gjson"
+	"golang.org/x/net/html"
+)
+
+type metadataContent struct {
+	Title     string            `json:"title"`
+	JsonLD    []json.RawMessage `json:"json_ld"`
+	Microdata []*microdataItem  `json:"microdata"`
+	OpenGraph map[string]string `json:"opengraph"`
+	// OpenGraphAll keeps every value of the OpenGraph arrays (several og:image)
+	OpenGraphAll map[string][]string `json:"opengraph_all"`
+	Twitter      map[string]string   `json:"twitter"`
+	Meta         map[string]string   `json:"meta"`
+	Links        []*metadataLink     `json:"links"`
+}
+
+// microdataItem follows the JSON form of the WHATWG microdata spec
+type microdataItem struct {
+	Type       []string                 `json:"type,omitempty"`
+	ID         string                   `json:"id,omitempty"`
+	Properties map[string][]interface{} `json:"properties"`
+}
+
+type metadataLink struct {
+	Rel      string `json:"rel"`
+	Href     string `json:"href"`
+	Type     string `json:"type,omitempty"`
+	Hreflang string `json:"hreflang,omitempty"`
+	Title    string `json:"title,omitempty"`
+}
+
+// NewMetadata collects the structured metadata of the HTML body (JSON-LD
+// blocks, microdata items, OpenGraph/Twitter/named meta tags and <link rel>)
+// into one JSON document, so models address it with regular gjson paths like
+// "opengraph.title" or "json_ld.#(@type==Product).name".
+func NewMetadata(body []byte, logger logger.Logger) *engineParser[*gjson.Result] {
+	document, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		logger.Errorw("unable to parse html for metadata", "error", err.Error())
+		return NewJson(nil, logger)
+	}
+
+	jsonBody, err := json.Marshal(extractMetadata(document, logger))
+	if err != nil {
+		logger.Errorw("unable to marshal metadata", "error", err.Error())
+		return NewJson(nil, logger)
+	}
+
+	return NewJson(jsonBody, logger)
+}
+
+func extractMetadata(document *goquery.Document, logger logger.Logger) *metadataContent {
+	content := &metadataContent{
+		Title:        strings.TrimSpace(document.Find("title").First().Text()),
+		JsonLD:       []json.RawMessage{},
+		Microdata:    []*microdataItem{},
+		OpenGraph:    map[string]string{},
+		OpenGraphAll: map[string][]string{},
+		Twitter:      map[string]string{},
+		Meta:         map[string]string{},
+		Links:        []*metadataLink{},
+	}
+
+	document.Find(`script[type="application/ld+json"]`).Each(func(_ int, script *goquery.Selection) {
+		block := []byte(strings.TrimSpace(script.Text()))
+		if !json.Valid(block) {
+			logger.Debugw("skip invalid json-ld block", "content", string(block))
+			return
+		}
+
+		// one script can hold a list of entities
+		var list []json.RawMessage
+		if json.Unmarshal(block, &list) == nil {
+			content.JsonLD = append(content.JsonLD, list...)
+			return
+		}
+		content.JsonLD = append(content.JsonLD, block)
+	})
+
+	document.Find("[itemscope]").Each(func(_ int, scope *goquery.Selection) {
+		// nested items are properties of their parent
+		if _, isProperty := scope.Attr("itemprop"); isProperty && scope.ParentsFiltered("[itemscope]").Length() > 0 {
+			return
+		}
+		content.Microdata = append(content.Microdata, microdata(scope.Nodes[0]))
+	})
+
+	document.Find("meta[content]").Each(func(_ int, meta *goquery.Selection) {
+		value, _ := meta.Attr("content")
+		name := meta.AttrOr("property", meta.AttrOr("name", ""))
+		if name == "" {
+			return
+		}
+
+		switch lower := strings.ToLower(name); {
+		case strings.HasPrefix(lower, "og:"):
+			content.addOpenGraph(lower[len("og:"):], value)
+		case strings.HasPrefix(lower, "twitter:"):
+			setFirst(content.Twitter, lower[len("twitter:"):], value)
+		case strings.HasPrefix(lower, "article:"), strings.HasPrefix(lower, "product:"), strings.HasPrefix(lower, "book:"), strings.HasPrefix(lower, "profile:"):
+			content.addOpenGraph(lower, value)
+		case meta.AttrOr("name", "") != "":
+			setFirst(content.Meta, lower, value)
+		}
+	})
+
+	document.Find("link[rel][href]").Each(func(_ int, link *goquery.Selection) {
+		content.Links = append(content.Links, &metadataLink{
+			Rel:      link.AttrOr("rel", ""),
+			Href:     link.AttrOr("href", ""),
+			Type:     link.AttrOr("type", ""),
+			Hreflang: link.AttrOr("hreflang", ""),
+			Title:    link.AttrOr("title", ""),
+		})
+	})
+
+	return content
+}
+
+func (c *metadataContent) addOpenGraph(key string, value string) {
+	setFirst(c.OpenGraph, key, value)
+	c.OpenGraphAll[key] = append(c.OpenGraphAll[key], value)
+}
+
+// setFirst keeps the first value of repeated tags, opengraph_all has the rest
+func setFirst(values map[string]string, key string, value string) {
+	if _, ok := values[key]; !ok {
+		values[key] = value
+	}
+}
+
+func microdata(scope *html.Node) *microdataItem {
+	item := &microdataItem{
+		Type:       strings.Fields(attr(scope, "itemtype")),
+		ID:         attr(scope, "itemid"),
+		Properties: map[string][]interface{}{},
+	}
+
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+
+			_, nested := attrValue(child, "itemscope")
+			if names := strings.Fields(attr(child, "itemprop")); len(names) > 0 {
+				var value interface{}
+				if nested {
+					value = microdata(child)
+				} else {
+					value = microdataValue(child)
+				}
+				for _, name := range names {
+					item.Properties[name] = append(item.Properties[name], value)
+				}
+			}
+
+			// properties of the nested item belong to it
+			if !nested {
+				walk(child)
+			}
+		}
+	}
+	walk(scope)
+
+	return item
+}
+
+// microdataValue reads the property value from the attribute defined by the
+// element type, text content otherwise
+func microdataValue(node *html.Node) string {
+	switch node.Data {
+	case "meta":
+		return attr(node, "content")
+	case "audio", "embed", "iframe", "img", "source", "track", "video":
+		return attr(node, "src")
+	case "a", "area", "link":
+		return attr(node, "href")
+	case "object":
+		return attr(node, "data")
+	case "data", "meter":
+		return attr(node, "value")
+	case "time":
+		if value, ok := attrValue(node, "datetime"); ok {
+			return value
+		}
+	}
+
+	return strings.Join(strings.Fields(goquery.NewDocumentFromNode(node).Text()), " ")
+}
+
+func attr(node *html.Node, name string) string {
+	value, _ := attrValue(node, name)
+	return value
+}
+
+func attrValue(node *html.Node, name string) (string, bool) {
+	for _, attribute := range node.Attr {
+		if attribute.Key == name {
+			return attribute.Val, true
+		}
+	}
+	return "", false
+}
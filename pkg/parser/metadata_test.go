@@ -0,0 +1,94 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+const metadataPage = `<html><head>
+	<title> Red Shoes </title>
+	<meta property="og:title" content="Red Shoes">
+	<meta property="og:image" content="https://example.com/1.jpg">
+	<meta property="og:image" content="https://example.com/2.jpg">
+	<meta property="product:price:amount" content="49.90">
+	<meta name="twitter:card" content="summary">
+	<meta name="description" content="Best shoes">
+	<link rel="canonical" href="https://example.com/shoes">
+	<link rel="alternate" hreflang="de" href="https://example.com/de/shoes">
+	<script type="application/ld+json">{"@context": "https://schema.org", "@type": "Product", "name": "Red Shoes", "offers": {"price": "49.90"}}</script>
+	<script type="application/ld+json">[{"@type": "BreadcrumbList"}, {"@type": "Organization", "name": "Shop"}]</script>
+	<script type="application/ld+json">{broken</script>
+</head><body>
+	<div itemscope itemtype="https://schema.org/Product" itemid="sku-1">
+		<span itemprop="name">Red   Shoes</span>
+		<img itemprop="image" src="/1.jpg">
+		<div itemprop="offers" itemscope itemtype="https://schema.org/Offer">
+			<meta itemprop="price" content="49.90">
+			<time itemprop="validFrom" datetime="2024-01-01">New year</time>
+		</div>
+	</div>
+</body></html>`
+
+func TestMetadataParser(t *testing.T) {
+	res, err := parser.NewMetadata([]byte(metadataPage), logger.Null).Parse(&config.Model{
+		ObjectConfig: &config.ObjectConfig{
+			Fields: map[string]*config.Field{
+				"title":       {BaseField: &config.BaseField{Type: config.String, Path: "title"}},
+				"og_title":    {BaseField: &config.BaseField{Type: config.String, Path: "opengraph.title"}},
+				"og_image":    {BaseField: &config.BaseField{Type: config.String, Path: "opengraph.image"}},
+				"og_images":   {BaseField: &config.BaseField{Type: config.RawString, Path: "opengraph_all.image"}},
+				"price":       {BaseField: &config.BaseField{Type: config.Float, Path: `opengraph.product:price:amount`}},
+				"card":        {BaseField: &config.BaseField{Type: config.String, Path: "twitter.card"}},
+				"description": {BaseField: &config.BaseField{Type: config.String, Path: "meta.description"}},
+				"canonical":   {BaseField: &config.BaseField{Type: config.String, Path: "links.#(rel==canonical).href"}},
+				"product":     {BaseField: &config.BaseField{Type: config.String, Path: "json_ld.#(@type==Product).name"}},
+				"blocks":      {BaseField: &config.BaseField{Type: config.Int, Path: "json_ld.#"}},
+				"org":         {BaseField: &config.BaseField{Type: config.String, Path: "json_ld.2.name"}},
+				"item_type":   {BaseField: &config.BaseField{Type: config.String, Path: "microdata.0.type.0"}},
+				"item_id":     {BaseField: &config.BaseField{Type: config.String, Path: "microdata.0.id"}},
+				"item_name":   {BaseField: &config.BaseField{Type: config.String, Path: "microdata.0.properties.name.0"}},
+				"item_image":  {BaseField: &config.BaseField{Type: config.String, Path: "microdata.0.properties.image.0"}},
+				"offer_type":  {BaseField: &config.BaseField{Type: config.String, Path: "microdata.0.properties.offers.0.type.0"}},
+				"offer_price": {BaseField: &config.BaseField{Type: config.Float, Path: "microdata.0.properties.offers.0.properties.price.0"}},
+				"offer_from":  {BaseField: &config.BaseField{Type: config.String, Path: "microdata.0.properties.offers.0.properties.validFrom.0"}},
+				"items":       {BaseField: &config.BaseField{Type: config.Int, Path: "microdata.#"}},
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"title": "Red Shoes",
+		"og_title": "Red Shoes",
+		"og_image": "https://example.com/1.jpg",
+		"og_images": "[\"https://example.com/1.jpg\",\"https://example.com/2.jpg\"]",
+		"price": 49.9,
+		"card": "summary",
+		"description": "Best shoes",
+		"canonical": "https://example.com/shoes",
+		"product": "Red Shoes",
+		"blocks": 3,
+		"org": "Shop",
+		"item_type": "https://schema.org/Product",
+		"item_id": "sku-1",
+		"item_name": "Red Shoes",
+		"item_image": "/1.jpg",
+		"offer_type": "https://schema.org/Offer",
+		"offer_price": 49.9,
+		"offer_from": "2024-01-01",
+		"items": 1
+	}`, string(res.Raw()))
+}
+
+func TestMetadataParserEmpty(t *testing.T) {
+	res, err := parser.NewMetadata([]byte(`<p>no metadata</p>`), logger.Null).Parse(&config.Model{
+		BaseField: &config.BaseField{Type: config.RawString, Path: "@this"},
+	}, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"title": "", "json_ld": [], "microdata": [], "opengraph": {}, "opengraph_all": {}, "twitter": {}, "meta": {}, "links": []}`, gjson.ParseBytes(res.Raw()).String())
+}
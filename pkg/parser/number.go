@@ -0,0 +1,271 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+)
+
+var (
+	// numberToken matches digits with group/decimal separators; a space
+	// separates groups only before exactly 3 digits, so "3 5" stays two numbers
+	numberToken    = regexp.MustCompile(`[-−]?\d+(?:[.,'’]\d+|[ \x{00a0}\x{202f}]\d{3}\b)*`)
+	magnitudeToken = regexp.MustCompile(`^ ?([KkMmBb])\b`)
+	isoCurrency    = regexp.MustCompile(`\b[A-Z]{3}\b`)
+	isoCode        = regexp.MustCompile(`^[A-Z]{3}$`)
+
+	numberGroupRemover = strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "", "'", "", "’", "", "−", "-")
+
+	magnitudes = map[string]float64{
+		"k": 1e3,
+		"m": 1e6,
+		"b": 1e9,
+	}
+
+	// decimalComma lists the languages which write 1.234,56; region tags
+	// are checked first, so de-CH keeps the decimal point
+	decimalComma = map[string]bool{
+		"de": true, "fr": true, "es": true, "it": true, "ru": true, "pt": true, "nl": true, "pl": true,
+		"tr": true, "uk": true, "sv": true, "cs": true, "da": true, "fi": true, "nb": true, "no": true,
+		"ro": true, "hu": true, "id": true, "el": true, "bg": true, "hr": true, "sk": true, "sl": true,
+		"lt": true, "lv": true, "et": true, "vi": true, "be": true, "kk": true, "sr": true,
+	}
+	decimalPoint = map[string]bool{
+		"en": true, "ja": true, "zh": true, "ko": true, "hi": true, "th": true, "he": true, "ms": true,
+		"tl": true, "ar": true, "fa": true, "bn": true, "ur": true, "sw": true,
+		"de-ch": true, "fr-ch": true, "it-ch": true, "de-li": true, "es-mx": true, "es-us": true,
+	}
+
+	// currencySymbols are checked in order, longer symbols first
+	currencySymbols = []struct {
+		symbol string
+		code   string
+	}{
+		{"US$", "USD"}, {"HK$", "HKD"}, {"R$", "BRL"}, {"C$", "CAD"}, {"A$", "AUD"}, {"CN¥", "CNY"},
+		{"zł", "PLN"}, {"Kč", "CZK"}, {"руб", "RUB"}, {"元", "CNY"},
+		{"$", "USD"}, {"€", "EUR"}, {"£", "GBP"}, {"¥", "JPY"}, {"₽", "RUB"}, {"₹", "INR"},
+		{"₩", "KRW"}, {"₺", "TRY"}, {"₴", "UAH"}, {"₪", "ILS"}, {"฿", "THB"}, {"₫", "VND"},
+	}
+	currencyCodes = map[string]bool{
+		"USD": true, "EUR": true, "GBP": true, "JPY": true, "CNY": true, "RUB": true, "INR": true, "KRW": true,
+		"TRY": true, "UAH": true, "PLN": true, "CHF": true, "CAD": true, "AUD": true, "NZD": true, "SEK": true,
+		"NOK": true, "DKK": true, "CZK": true, "HUF": true, "BRL": true, "MXN": true, "ZAR": true, "SGD": true,
+		"HKD": true, "ILS": true, "AED": true, "SAR": true, "THB": true, "IDR": true, "MYR": true, "PHP": true,
+		"VND": true, "ARS": true, "CLP": true, "COP": true, "RON": true, "BGN": true, "KZT": true, "BYN": true,
+	}
+)
+
+// localeDecimal returns the decimal separator of the locale, 0 when unknown
+func localeDecimal(locale string) byte {
+	tag := strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	for _, key := range []string{tag, strings.Split(tag, "-")[0]} {
+		if decimalPoint[key] {
+			return '.'
+		}
+		if decimalComma[key] {
+			return ','
+		}
+	}
+	return 0
+}
+
+// validateNumber checks the number settings at config load time
+func validateNumber(cfg *config.NumberConfig) error {
+	if cfg.Locale != "" && localeDecimal(cfg.Locale) == 0 {
+		return fmt.Errorf("unknown locale %q", cfg.Locale)
+	}
+	if cfg.Currency != "" && !isoCode.MatchString(cfg.Currency) {
+		return fmt.Errorf("currency %q is not an ISO code", cfg.Currency)
+	}
+	return nil
+}
+
+// guessDecimal picks the decimal separator of the token without locale: the
+// last one when both are used, a repeated one is a group separator. A single
+// dot is decimal ("4.125"), a single comma followed by exactly three digits
+// is a group separator ("1,234" -> 1234) unless the integer part is 0
+func guessDecimal(token string) byte {
+	lastDot, lastComma := strings.LastIndexByte(token, '.'), strings.LastIndexByte(token, ',')
+	if lastDot >= 0 && lastComma >= 0 {
+		if lastDot > lastComma {
+			return '.'
+		}
+		return ','
+	}
+
+	separator, last := byte('.'), lastDot
+	if lastComma >= 0 {
+		separator, last = ',', lastComma
+	}
+	if last < 0 {
+		return '.'
+	}
+
+	integer := strings.TrimLeft(token[:last], "-0")
+	group := strings.Count(token, string(separator)) > 1 ||
+		(separator == ',' && len(token)-last-1 == 3 && integer != "")
+	if group {
+		// the decimal separator is the other one
+		if separator == '.' {
+			return ','
+		}
+		return '.'
+	}
+	return separator
+}
+
+// parseNumber finds the first number in the text, currency signs, percents
+// and other words around it are ignored
+func parseNumber(text string, cfg *config.NumberConfig) (float64, bool) {
+	token, multiplier, ok := normalizeNumber(text, cfg)
+	if !ok {
+		return 0, false
+	}
+
+	value, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value * multiplier, true
+}
+
+// normalizeNumber returns the first number of the text in the Go notation and
+// its magnitude multiplier
+func normalizeNumber(text string, cfg *config.NumberConfig) (string, float64, bool) {
+	location := numberToken.FindStringIndex(text)
+	if location == nil {
+		return "", 0, false
+	}
+
+	token := numberGroupRemover.Replace(text[location[0]:location[1]])
+	if location[0] > 0 && strings.HasPrefix(token, "-") {
+		// a dash after a word is a hyphen ("SKU-123"), not a sign
+		if previous, _ := utf8.DecodeLastRuneInString(text[:location[0]]); unicode.IsLetter(previous) || unicode.IsDigit(previous) {
+			token = token[1:]
+		}
+	}
+	decimal := localeDecimal(cfg.Locale)
+	if decimal == 0 {
+		decimal = guessDecimal(token)
+	}
+	group := byte(',')
+	if decimal == ',' {
+		group = '.'
+	}
+
+	token = strings.ReplaceAll(token, string(group), "")
+	if strings.Count(token, string(decimal)) > 1 {
+		return "", 0, false
+	}
+	token = strings.Replace(token, string(decimal), ".", 1)
+
+	multiplier := 1.0
+	if cfg.Magnitude {
+		if match := magnitudeToken.FindStringSubmatch(text[location[1]:]); match != nil {
+			multiplier = magnitudes[strings.ToLower(match[1])]
+		}
+	}
+	return token, multiplier, true
+}
+
+// parseCurrency finds the currency symbol or ISO code in the text
+func parseCurrency(text string) string {
+	for _, code := range isoCurrency.FindAllString(text, -1) {
+		if currencyCodes[code] {
+			return code
+		}
+	}
+	for _, currency := range currencySymbols {
+		if strings.Contains(text, currency.symbol) {
+			return currency.code
+		}
+	}
+	return ""
+}
+
+// numberValue parses numeric types, strictly unless the field has number settings
+func numberValue(text string, field *config.BaseField) builder.Interfacable {
+	if field.Type == config.Int || field.Type == config.Int64 {
+		return integerValue(text, field)
+	}
+
+	if field.Number == nil {
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return builder.NullValue
+		}
+		return builder.Number(value)
+	}
+
+	value, ok := parseNumber(text, field.Number)
+	if !ok {
+		return builder.NullValue
+	}
+	return builder.Number(value)
+}
+
+// integerValue keeps int/int64 exact, non-integral values follow the rounding of the field
+func integerValue(text string, field *config.BaseField) builder.Interfacable {
+	if field.Number == nil {
+		return builder.IntFromString(text, field.Rounding)
+	}
+
+	token, multiplier, ok := normalizeNumber(text, field.Number)
+	if !ok {
+		return builder.NullValue
+	}
+	if multiplier == 1 {
+		return builder.IntFromString(token, field.Rounding)
+	}
+
+	value, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return builder.NullValue
+	}
+	integer, ok := builder.ToInteger(value*multiplier, field.Rounding)
+	if !ok {
+		return builder.NullValue
+	}
+	return builder.Int(integer)
+}
+
+// validateRounding checks the rounding mode at config load time
+func validateRounding(rounding config.Rounding) error {
+	switch rounding {
+	case "", config.RoundHalf, config.RoundFloor, config.RoundCeil, config.RoundTruncate:
+		return nil
+	}
+	return fmt.Errorf("unknown rounding %q", rounding)
+}
+
+// moneyValue builds {"amount", "currency"}, the currency falls back to the
+// configured one
+func moneyValue(text string, field *config.BaseField) builder.Interfacable {
+	cfg := field.Number
+	if cfg == nil {
+		cfg = &config.NumberConfig{}
+	}
+
+	amount, ok := parseNumber(text, cfg)
+	if !ok {
+		return builder.NullValue
+	}
+
+	var currency builder.Interfacable = builder.NullValue
+	if code := parseCurrency(text); code != "" {
+		currency = builder.String(code)
+	} else if cfg.Currency != "" {
+		currency = builder.String(cfg.Currency)
+	}
+
+	return builder.Object(map[string]builder.Interfacable{
+		"amount":   builder.Number(amount),
+		"currency": currency,
+	})
+}
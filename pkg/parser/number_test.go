@@ -0,0 +1,136 @@
+package parser_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseText(t *testing.T, text string, field *config.BaseField) string {
+	body, err := json.Marshal(map[string]string{"value": text})
+	require.NoError(t, err)
+
+	field.Path = "value"
+	res, err := parser.NewJson(body, logger.Null).Parse(&config.Model{BaseField: field}, nil)
+	require.NoError(t, err)
+	return res.ToJson()
+}
+
+func TestNumberField(t *testing.T) {
+	for _, tc := range []struct {
+		text     string
+		cfg      *config.NumberConfig
+		expected string
+	}{
+		{text: "1.234,56", cfg: &config.NumberConfig{}, expected: "1234.56"},
+		{text: "1,234.56", cfg: &config.NumberConfig{}, expected: "1234.56"},
+		{text: "$12.99", cfg: &config.NumberConfig{}, expected: "12.99"},
+		{text: "12 %", cfg: &config.NumberConfig{}, expected: "12"},
+		{text: "1.2K", cfg: &config.NumberConfig{Magnitude: true}, expected: "1200"},
+		{text: "3.5M views", cfg: &config.NumberConfig{Magnitude: true}, expected: "3500000"},
+		{text: "1.2K", cfg: &config.NumberConfig{}, expected: "1.2"},
+		{text: "0.125", cfg: &config.NumberConfig{}, expected: "0.125"},
+		{text: "$0.999", cfg: &config.NumberConfig{}, expected: "0.999"},
+		{text: "Rating 4.125", cfg: &config.NumberConfig{}, expected: "4.125"},
+		{text: "0,125", cfg: &config.NumberConfig{}, expected: "0.125"},
+		{text: "1,234", cfg: &config.NumberConfig{}, expected: "1234"},
+		{text: "1.234.567", cfg: &config.NumberConfig{}, expected: "1234567"},
+		{text: "Price: 1 234,50 € incl. VAT", cfg: &config.NumberConfig{Locale: "fr"}, expected: "1234.5"},
+		{text: "1.234", cfg: &config.NumberConfig{Locale: "en"}, expected: "1.234"},
+		{text: "1.234", cfg: &config.NumberConfig{Locale: "de_DE"}, expected: "1234"},
+		{text: "CHF 1'234.50", cfg: &config.NumberConfig{Locale: "de-CH"}, expected: "1234.5"},
+		{text: "−5,5 °C", cfg: &config.NumberConfig{}, expected: "-5.5"},
+		{text: "SKU-123", cfg: &config.NumberConfig{}, expected: "123"},
+		{text: "sold out", cfg: &config.NumberConfig{}, expected: "null"},
+		{text: "$12.99", cfg: nil, expected: "null"},
+	} {
+		t.Run(tc.text, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parseText(t, tc.text, &config.BaseField{Type: config.Float, Number: tc.cfg}))
+		})
+	}
+}
+
+func TestMoneyField(t *testing.T) {
+	for _, tc := range []struct {
+		text     string
+		cfg      *config.NumberConfig
+		expected string
+	}{
+		{text: "$12.99", expected: `{"amount": 12.99, "currency": "USD"}`},
+		{text: "1.234,56 €", expected: `{"amount": 1234.56, "currency": "EUR"}`},
+		{text: "EUR 5", expected: `{"amount": 5, "currency": "EUR"}`},
+		{text: "R$ 10,50", expected: `{"amount": 10.5, "currency": "BRL"}`},
+		{text: "99", cfg: &config.NumberConfig{Currency: "GBP"}, expected: `{"amount": 99, "currency": "GBP"}`},
+		{text: "99", expected: `{"amount": 99, "currency": null}`},
+		{text: "free", expected: `null`},
+	} {
+		t.Run(tc.text, func(t *testing.T) {
+			assert.JSONEq(t, tc.expected, parseText(t, tc.text, &config.BaseField{Type: config.Money, Number: tc.cfg}))
+		})
+	}
+}
+
+func TestNumberFieldPrepare(t *testing.T) {
+	for _, cfg := range []*config.NumberConfig{
+		{Locale: "klingon"},
+		{Currency: "euro"},
+	} {
+		err := parser.PrepareModel(&config.Model{
+			BaseField: &config.BaseField{Type: config.Money, Number: cfg},
+		}, "model")
+		assert.ErrorContains(t, err, "model.base_field.number")
+	}
+}
+
+func TestIntegerField(t *testing.T) {
+	for _, tc := range []struct {
+		text     string
+		field    *config.BaseField
+		expected string
+	}{
+		{text: "12", field: &config.BaseField{Type: config.Int}, expected: "12"},
+		{text: "12.0", field: &config.BaseField{Type: config.Int}, expected: "12"},
+		{text: "12.7", field: &config.BaseField{Type: config.Int}, expected: "null"},
+		{text: "12.7", field: &config.BaseField{Type: config.Int, Rounding: config.RoundHalf}, expected: "13"},
+		{text: "12.7", field: &config.BaseField{Type: config.Int64, Rounding: config.RoundFloor}, expected: "12"},
+		{text: "9007199254740993", field: &config.BaseField{Type: config.Int64}, expected: "9007199254740993"},
+		{text: "ID 9,007,199,254,740,993", field: &config.BaseField{Type: config.Int64, Number: &config.NumberConfig{}}, expected: "9007199254740993"},
+		{text: "1.25K", field: &config.BaseField{Type: config.Int, Number: &config.NumberConfig{Magnitude: true}}, expected: "1250"},
+		{text: "1.2345K", field: &config.BaseField{Type: config.Int, Number: &config.NumberConfig{Magnitude: true}}, expected: "null"},
+	} {
+		t.Run(tc.text, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parseText(t, tc.text, tc.field))
+		})
+	}
+}
+
+func TestIntegerFieldJson(t *testing.T) {
+	body := []byte(`{"id": 9007199254740993, "count": 12.5}`)
+	res, err := parser.NewJson(body, logger.Null).Parse(&config.Model{
+		ObjectConfig: &config.ObjectConfig{
+			Fields: map[string]*config.Field{
+				"id": {BaseField: &config.BaseField{
+					Type:      config.Int64,
+					Path:      "id",
+					Condition: "fRes == 9007199254740993 && fRes != 9007199254740992",
+				}},
+				"count": {BaseField: &config.BaseField{Type: config.Int, Path: "count", Rounding: config.RoundCeil}},
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id": 9007199254740993, "count": 13}`, res.ToJson())
+	assert.Contains(t, res.ToJson(), "9007199254740993")
+}
+
+func TestIntegerFieldPrepare(t *testing.T) {
+	err := parser.PrepareModel(&config.Model{
+		BaseField: &config.BaseField{Type: config.Int, Rounding: "nearest"},
+	}, "model")
+	assert.ErrorContains(t, err, "model.base_field.rounding")
+}
@@ -7,11 +7,15 @@ import (
 	"github.com/PxyUp/fitter/pkg/logger"
 	"github.com/PxyUp/fitter/pkg/utils"
 	"github.com/tidwall/gjson"
+	"golang.org/x/net/html"
 	"slices"
 	"strconv"
 	"sync"
 )
 
+// streamWindow is the amount of array items built concurrently in streaming mode
+const streamWindow = 64
+
 func IsZero[T comparable](v T) bool {
 	return v == *new(T)
 }
@@ -21,10 +25,17 @@ type engineParser[T comparable] struct {
 	getAll     func(T, string) []T
 	getOne     func(T, string) T
 	getText    func(T) string
+	// getHTML returns the inner html of the node for markdown fields, parsers
+	// without it convert the text which holds html (json strings, CDATA)
+	getHTML func(T) string
 
-	customFillUpBaseField func(T, *config.BaseField) builder.Interfacable
+	customFillUpBaseField func(T, *config.BaseField, urlBase) builder.Interfacable
 	logger                logger.Logger
 	ctx                   context.Context
+	// baseHref of the <base> tag of html documents
+	baseHref string
+	// getTable returns the node of table arrays, nil for parsers without tables
+	getTable func(T, string) *html.Node
 }
 
 // WithContext attaches the request context so nested fetches (generated
@@ -42,6 +53,14 @@ func (e *engineParser[T]) context() context.Context {
 	return e.ctx
 }
 
+func (e *engineParser[T]) urlBase() urlBase {
+	page, _ := e.context().Value(pageURLKey{}).(string)
+	return urlBase{
+		page: page,
+		href: e.baseHref,
+	}
+}
+
 // checkCondition reports whether the field must be kept; evaluation errors
 // omit the field instead of failing the whole parse. source is exposed to the
 // expression as fSrc
@@ -80,11 +99,23 @@ func unomit(value builder.Interfacable) builder.Interfacable {
 }
 
 func (e *engineParser[T]) fillUpBaseField(source T, field *config.BaseField) builder.Interfacable {
+	base := e.urlBase()
+	convert := func(text string) builder.Interfacable {
+		return textValue(text, field, base)
+	}
+
 	if IsZero(source) {
-		return builder.NullValue
+		return missingValue(field, convert)
 	}
 
-	text := e.getText(source)
+	if field.Type == config.Markdown && e.getHTML != nil {
+		return baseFieldValue(e.getHTML(source), field, convert)
+	}
+
+	return baseFieldValue(e.getText(source), field, convert)
+}
+
+func textValue(text string, field *config.BaseField, base urlBase) builder.Interfacable {
 	switch field.Type {
 	case config.Null:
 		return builder.NullValue
@@ -99,11 +130,13 @@ func (e *engineParser[T]) fillUpBaseField(source T, field *config.BaseField) bui
 		}
 		return builder.Bool(boolValue)
 	case config.Float, config.Float64, config.Int, config.Int64:
-		float32Value, err := strconv.ParseFloat(text, 64)
-		if err != nil {
-			return builder.NullValue
-		}
-		return builder.Number(float32Value)
+		return numberValue(text, field)
+	case config.Money:
+		return moneyValue(text, field)
+	case config.URL:
+		return urlValue(text, field, base)
+	case config.Markdown:
+		return builder.String(htmlToMarkdown(text, base), false)
 	case config.Array:
 		return builder.PureString(text)
 	case config.Object:
@@ -121,31 +154,26 @@ func (e *engineParser[T]) buildObjectField(source T, objectConfig *config.Object
 		}
 	}
 
+	keys := make([]string, 0, len(objectConfig.Fields))
+	for key := range objectConfig.Fields {
+		keys = append(keys, key)
+	}
+	// stable order keeps sequential_fields evaluation deterministic
+	slices.Sort(keys)
+
 	kv := make(map[string]builder.Interfacable)
-	var wg sync.WaitGroup
 	var mutex sync.Mutex
 
-	for lKey, lValue := range objectConfig.Fields {
-		key := lKey
-		value := lValue
-		wg.Add(1)
-		go func(k string, v *config.Field) {
-			defer wg.Done()
-
-			resolved := e.resolveField(source, v, nil, input)
-			if builder.IsOmitted(resolved) {
-				return
-			}
-
-			mutex.Lock()
-			kv[k] = resolved
-			mutex.Unlock()
-
-		}(key, value)
-
-	}
+	resolveConcurrently(len(keys), func(i int) {
+		resolved := e.resolveField(source, objectConfig.Fields[keys[i]], nil, input)
+		if builder.IsOmitted(resolved) {
+			return
+		}
 
-	wg.Wait()
+		mutex.Lock()
+		kv[keys[i]] = resolved
+		mutex.Unlock()
+	})
 
 	return builder.Object(kv)
 }
@@ -184,7 +212,7 @@ func (e *engineParser[T]) buildBaseField(source T, field *config.BaseField, inde
 
 	var tempValue builder.Interfacable
 	if e.customFillUpBaseField != nil {
-		tempValue = e.customFillUpBaseField(source, field)
+		tempValue = e.customFillUpBaseField(source, field, e.urlBase())
 	} else {
 		tempValue = e.fillUpBaseField(source, field)
 	}
@@ -221,7 +249,7 @@ func (e *engineParser[T]) resolveField(parent T, field *config.Field, index *uin
 			}
 		}
 
-		return e.buildArrayField(e.getAll(parent, field.ArrayConfig.RootPath), field.ArrayConfig, input)
+		return e.buildArrayFrom(parent, field.ArrayConfig, input)
 	}
 
 	return builder.NullValue
@@ -234,23 +262,16 @@ func (e *engineParser[T]) buildStaticArray(cfg *config.StaticArrayConfig, input
 	}
 	values := make([]builder.Interfacable, length)
 
-	var wg sync.WaitGroup
-
-	for lKey, lValue := range cfg.Items {
-		key := lKey
-		value := lValue
-		wg.Add(1)
-		go func(k uint32, v *config.Field) {
-			defer wg.Done()
-
-			arrIndex := k
-			values[k] = e.resolveField(e.parserBody, v, &arrIndex, input)
-
-		}(key, value)
-
+	indexes := make([]uint32, 0, len(cfg.Items))
+	for key := range cfg.Items {
+		indexes = append(indexes, key)
 	}
+	slices.Sort(indexes)
 
-	wg.Wait()
+	resolveConcurrently(len(indexes), func(i int) {
+		arrIndex := indexes[i]
+		values[arrIndex] = e.resolveField(e.parserBody, cfg.Items[arrIndex], &arrIndex, input)
+	})
 
 	// static arrays are positional: omitted/unset slots stay null instead of shifting indexes
 	for i, v := range values {
@@ -270,7 +291,28 @@ func (e *engineParser[T]) buildArray(array *config.ArrayConfig, input builder.In
 		}
 	}
 
-	return e.buildArrayField(e.getAll(e.parserBody, array.RootPath), array, input)
+	return e.buildArrayFrom(e.parserBody, array, input)
+}
+
+// buildArrayFrom resolves the array against the parent node
+func (e *engineParser[T]) buildArrayFrom(parent T, cfg *config.ArrayConfig, input builder.Interfacable) builder.Interfacable {
+	if cfg.Table != nil && e.getTable != nil {
+		rowsParser, rowsCfg := e.tableParser(parent, cfg)
+		return rowsParser.buildArrayField(rowsParser.getAll(rowsParser.parserBody, ""), rowsCfg, input)
+	}
+
+	return e.buildArrayField(e.getAll(parent, cfg.RootPath), cfg, input)
+}
+
+// tableParser converts the table of the array into json rows, the items are
+// built from them by the json parser
+func (e *engineParser[T]) tableParser(parent T, cfg *config.ArrayConfig) (*engineParser[*gjson.Result], *config.ArrayConfig) {
+	rowsParser := NewJson(tableRows(e.getTable(parent, cfg.RootPath), cfg.Table), e.logger).WithContext(e.ctx)
+
+	rowsCfg := *cfg
+	rowsCfg.RootPath = ""
+	rowsCfg.Table = nil
+	return rowsParser, &rowsCfg
 }
 
 func (e *engineParser[T]) buildObject(object *config.ObjectConfig, input builder.Interfacable) builder.Interfacable {
@@ -308,6 +350,65 @@ func (e *engineParser[T]) Parse(model *config.Model, input builder.Interfacable)
 	}, nil
 }
 
+// Stream resolves the model like Parse, but array models hand every item to
+// emit as soon as it is built instead of materialising the whole array;
+// emit returning false stops the stream. Other models emit their single value
+func (e *engineParser[T]) Stream(model *config.Model, input builder.Interfacable, emit func(builder.Interfacable) bool) error {
+	if model.ArrayConfig == nil || model.ArrayConfig.StaticConfig != nil {
+		res, err := e.Parse(model, input)
+		if err != nil {
+			return err
+		}
+
+		emit(builder.ToJsonable(res.RawResult))
+		return nil
+	}
+
+	if IsZero(e.parserBody) {
+		return nil
+	}
+
+	array := model.ArrayConfig
+	if array.Condition != "" {
+		sourceVal := e.sourceValue(e.parserBody)
+		if !e.checkCondition(array.Condition, sourceVal, sourceVal, nil, input) {
+			return nil
+		}
+	}
+
+	if array.Table != nil && e.getTable != nil {
+		rowsParser, rowsCfg := e.tableParser(e.parserBody, array)
+		rowsParser.streamArrayField(rowsParser.getAll(rowsParser.parserBody, ""), rowsCfg, input, emit)
+		return nil
+	}
+
+	e.streamArrayField(e.getAll(e.parserBody, array.RootPath), array, input, emit)
+	return nil
+}
+
+// streamArrayField builds the array in windows of streamWindow items, so only
+// one window is kept in memory; items are emitted in order with the same
+// length_limit and item_condition semantics as buildArrayField
+func (e *engineParser[T]) streamArrayField(parent []T, cfg *config.ArrayConfig, input builder.Interfacable, emit func(builder.Interfacable) bool) {
+	if cfg.Reverse {
+		slices.Reverse(parent)
+	}
+
+	size := arraySize(parent, cfg)
+	for from := 0; from < size; from += streamWindow {
+		to := min(from+streamWindow, size)
+		for i, v := range e.buildArrayItems(parent, from, to, cfg, input) {
+			item, ok := e.acceptArrayItem(parent, from+i, v, cfg, input)
+			if !ok {
+				continue
+			}
+			if !emit(item) {
+				return
+			}
+		}
+	}
+}
+
 func (e *engineParser[T]) buildArrayField(parent []T, cfg *config.ArrayConfig, input builder.Interfacable) builder.Interfacable {
 	if cfg.StaticConfig != nil {
 		return e.buildStaticArray(cfg.StaticConfig, input)
@@ -317,134 +418,129 @@ func (e *engineParser[T]) buildArrayField(parent []T, cfg *config.ArrayConfig, i
 		slices.Reverse(parent)
 	}
 
-	size := len(parent)
+	size := arraySize(parent, cfg)
+
+	return finalizeArrayItems(e, parent, e.buildArrayItems(parent, 0, size, cfg, input), cfg, input)
+}
+
+// arraySize is the declared length of the array: length_limit when set (even
+// above the amount of source elements), otherwise the amount of source elements
+func arraySize[T comparable](parent []T, cfg *config.ArrayConfig) int {
 	if cfg.LengthLimit > 0 {
-		size = int(cfg.LengthLimit)
+		return int(cfg.LengthLimit)
 	}
 
-	if cfg.ItemConfig.Field != nil {
-		return FillArrayBaseField(e, parent, size, cfg, input)
-	}
+	return len(parent)
+}
 
-	if cfg.ItemConfig.ArrayConfig != nil {
-		return FillArrayArrayField(e, parent, size, e.getAll, cfg, input)
-	}
+// buildArrayItems builds the items [from, to) of the array concurrently;
+// slots without a source element stay nil
+func (e *engineParser[T]) buildArrayItems(parent []T, from int, to int, cfg *config.ArrayConfig, input builder.Interfacable) []builder.Interfacable {
+	values := make([]builder.Interfacable, to-from)
 
-	return FillArrayObjectField(e, parent, size, cfg, input)
+	resolveConcurrently(min(to, len(parent))-from, func(i int) {
+		values[i] = e.buildArrayItem(parent[from+i], from+i, cfg, input)
+	})
+
+	return values
 }
 
-// finalizeArrayItems drops items rejected by a condition (omitted or failing
-// item_condition); nil slots stay null to preserve the declared size when
-// length_limit exceeds the amount of source elements. parent aligns with
-// values by index and feeds fSrc for item_condition
-func finalizeArrayItems[T comparable](engine *engineParser[T], parent []T, values []builder.Interfacable, cfg *config.ArrayConfig, input builder.Interfacable) builder.Interfacable {
-	res := make([]builder.Interfacable, 0, len(values))
-	for i, v := range values {
-		if v == nil {
-			v = builder.NullValue
-		}
+func (e *engineParser[T]) buildArrayItem(selection T, index int, cfg *config.ArrayConfig, input builder.Interfacable) builder.Interfacable {
+	arrIndex := uint32(index)
 
-		if builder.IsOmitted(v) {
-			continue
-		}
+	if cfg.ItemConfig.Field != nil {
+		return e.buildBaseField(selection, cfg.ItemConfig.Field, &arrIndex, input)
+	}
 
-		if cfg.ItemCondition != "" {
-			arrIndex := uint32(i)
-			var src builder.Interfacable = builder.NullValue
-			if i < len(parent) {
-				src = engine.sourceValue(parent[i])
-			}
-			if !engine.checkCondition(cfg.ItemCondition, v, src, &arrIndex, input) {
-				continue
+	if inner := cfg.ItemConfig.ArrayConfig; inner != nil {
+		if inner.Condition != "" {
+			sourceVal := e.sourceValue(selection)
+			if !e.checkCondition(inner.Condition, sourceVal, sourceVal, &arrIndex, input) {
+				return builder.OmitValue
 			}
 		}
 
-		res = append(res, v)
+		return e.buildArrayFrom(selection, inner, input)
 	}
 
-	return builder.Array(res)
+	return e.buildObjectField(selection, cfg.ItemConfig, &arrIndex, input)
 }
 
-func FillArrayBaseField[T comparable](engine *engineParser[T], parent []T, size int, cfg *config.ArrayConfig, input builder.Interfacable) builder.Interfacable {
-	values := make([]builder.Interfacable, size)
-
-	var wg sync.WaitGroup
-	for iL, sL := range parent {
-		if iL >= size {
-			break
-		}
+// acceptArrayItem reports whether the built item at index stays in the array:
+// items omitted by a condition or failing item_condition are dropped; nil
+// slots become null to preserve the declared size when length_limit exceeds
+// the amount of source elements. parent feeds fSrc for item_condition
+func (e *engineParser[T]) acceptArrayItem(parent []T, index int, value builder.Interfacable, cfg *config.ArrayConfig, input builder.Interfacable) (builder.Interfacable, bool) {
+	if value == nil {
+		value = builder.NullValue
+	}
 
-		i := iL
-		s := sL
-		wg.Add(1)
-		go func(index int, selection T) {
-			defer wg.Done()
+	if builder.IsOmitted(value) {
+		return nil, false
+	}
 
-			arrIndex := uint32(index)
+	if cfg.ItemCondition != "" {
+		arrIndex := uint32(index)
+		var src builder.Interfacable = builder.NullValue
+		if index < len(parent) {
+			src = e.sourceValue(parent[index])
+		}
+		if !e.checkCondition(cfg.ItemCondition, value, src, &arrIndex, input) {
+			return nil, false
+		}
+	}
 
-			values[index] = engine.buildBaseField(selection, cfg.ItemConfig.Field, &arrIndex, input)
-		}(i, s)
+	return value, true
+}
 
+// finalizeArrayItems keeps the accepted items; values aligns with parent by index
+func finalizeArrayItems[T comparable](engine *engineParser[T], parent []T, values []builder.Interfacable, cfg *config.ArrayConfig, input builder.Interfacable) builder.Interfacable {
+	res := make([]builder.Interfacable, 0, len(values))
+	for i, v := range values {
+		if item, ok := engine.acceptArrayItem(parent, i, v, cfg, input); ok {
+			res = append(res, item)
+		}
 	}
-	wg.Wait()
 
-	return finalizeArrayItems(engine, parent, values, cfg, input)
+	return builder.Array(res)
 }
 
+// FillArrayBaseField builds the first size items of the array with the base
+// field item config.
+//
+// Deprecated: the array fields are built by the parser, kept for external callers
+func FillArrayBaseField[T comparable](engine *engineParser[T], parent []T, size int, cfg *config.ArrayConfig, input builder.Interfacable) builder.Interfacable {
+	return finalizeArrayItems(engine, parent, engine.buildArrayItems(parent, 0, size, cfg, input), cfg, input)
+}
+
+// FillArrayArrayField builds the first size items of the array with the
+// nested array item config, fn selects the elements of the nested array.
+//
+// Deprecated: the array fields are built by the parser, kept for external callers
 func FillArrayArrayField[T comparable](engine *engineParser[T], parent []T, size int, fn func(T, string) []T, cfg *config.ArrayConfig, input builder.Interfacable) builder.Interfacable {
 	inner := cfg.ItemConfig.ArrayConfig
 	values := make([]builder.Interfacable, size)
 
-	var wg sync.WaitGroup
-	for iL, iS := range parent {
-		if iL >= size {
-			break
-		}
-
-		i := iL
-		s := iS
-		wg.Add(1)
-		go func(index int, selection T) {
-			defer wg.Done()
-
-			if inner.Condition != "" {
-				arrIndex := uint32(index)
-				sourceVal := engine.sourceValue(selection)
-				if !engine.checkCondition(inner.Condition, sourceVal, sourceVal, &arrIndex, input) {
-					values[index] = builder.OmitValue
-					return
-				}
+	resolveConcurrently(min(size, len(parent)), func(index int) {
+		if inner.Condition != "" {
+			arrIndex := uint32(index)
+			sourceVal := engine.sourceValue(parent[index])
+			if !engine.checkCondition(inner.Condition, sourceVal, sourceVal, &arrIndex, input) {
+				values[index] = builder.OmitValue
+				return
 			}
+		}
 
-			values[index] = engine.buildArrayField(fn(selection, inner.RootPath), inner, input)
-		}(i, s)
-	}
-	wg.Wait()
+		values[index] = engine.buildArrayField(fn(parent[index], inner.RootPath), inner, input)
+	})
 
 	return finalizeArrayItems(engine, parent, values, cfg, input)
 }
 
+// FillArrayObjectField builds the first size items of the array with the
+// object item config.
+//
+// Deprecated: the array fields are built by the parser, kept for external callers
 func FillArrayObjectField[T comparable](engine *engineParser[T], parent []T, size int, cfg *config.ArrayConfig, input builder.Interfacable) builder.Interfacable {
-	values := make([]builder.Interfacable, size)
-
-	var wg sync.WaitGroup
-	for iL, iS := range parent {
-		if iL >= size {
-			break
-		}
-
-		i := iL
-		s := iS
-		wg.Add(1)
-		go func(index int, selection T) {
-			defer wg.Done()
-
-			arrIndex := uint32(index)
-
-			values[index] = engine.buildObjectField(selection, cfg.ItemConfig, &arrIndex, input)
-		}(i, s)
-	}
-	wg.Wait()
-
-	return finalizeArrayItems(engine, parent, values, cfg, input)
+	return finalizeArrayItems(engine, parent, engine.buildArrayItems(parent, 0, size, cfg, input), cfg, input)
 }
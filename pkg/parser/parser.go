@@ -31,12 +31,27 @@ var (
 	PDFFactory Factory = func(ctx context.Context, bytes []byte, logger logger.Logger) Parser {
 		return NewPDF(bytes, logger.With("parser", "pdf")).WithContext(ctx)
 	}
+
+	ReadabilityFactory Factory = func(ctx context.Context, bytes []byte, logger logger.Logger) Parser {
+		// links of the article are resolved against the page url
+		page, _ := ctx.Value(pageURLKey{}).(string)
+		return newReadability(bytes, urlBase{page: page}, logger.With("parser", "readability")).WithContext(ctx)
+	}
+
+	FeedFactory Factory = func(ctx context.Context, bytes []byte, logger logger.Logger) Parser {
+		return NewFeed(bytes, logger.With("parser", "feed")).WithContext(ctx)
+	}
+
+	MetadataFactory Factory = func(ctx context.Context, bytes []byte, logger logger.Logger) Parser {
+		return NewMetadata(bytes, logger.With("parser", "metadata")).WithContext(ctx)
+	}
 )
 
 type Factory func(context.Context, []byte, logger.Logger) Parser
 
 type Parser interface {
 	Parse(model *config.Model, input builder.Interfacable) (*ParseResult, error)
+	Stream(model *config.Model, input builder.Interfacable, emit func(builder.Interfacable) bool) error
 }
 
 var (
@@ -175,8 +190,12 @@ func fillUpBaseField(source gjson.Result, field *config.BaseField) builder.Inter
 			return builder.NullValue
 		}
 		return builder.Bool(source.Bool())
-	case config.Float, config.Float64, config.Int, config.Int64:
+	case config.Int, config.Int64:
+		return integerValue(source.String(), field)
+	case config.Float, config.Float64:
 		return builder.Number(source.Float())
+	case config.URL:
+		return urlValue(source.String(), field, urlBase{})
 	case config.Array, config.Object:
 		return builder.ToJsonable([]byte(source.String()))
 	}
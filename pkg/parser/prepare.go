@@ -0,0 +1,163 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/utils"
+)
+
+// PrepareModel runs once at config load time: it walks the model tree,
+// validates the field settings and compiles every placeholder-free
+// expression into the program cache, so conditions and calculated fields of
+// large arrays are not compiled per item. path prefixes the errors
+func PrepareModel(model *config.Model, path string) error {
+	if model == nil {
+		return nil
+	}
+
+	if err := prepareBaseField(model.BaseField, path+".base_field"); err != nil {
+		return err
+	}
+	if err := prepareObject(model.ObjectConfig, path+".object_config"); err != nil {
+		return err
+	}
+	return prepareArray(model.ArrayConfig, path+".array_config")
+}
+
+func prepareExpression(expression string, path string) error {
+	if err := utils.ValidateExpression(expression); err != nil {
+		return fmt.Errorf("%s: invalid expression %q: %w", path, expression, err)
+	}
+	return nil
+}
+
+func prepareBaseField(field *config.BaseField, path string) error {
+	if field == nil {
+		return nil
+	}
+
+	if err := prepareExpression(field.Condition, path+".condition"); err != nil {
+		return err
+	}
+
+	for i, transform := range field.Transforms {
+		if err := validateTransform(transform); err != nil {
+			return fmt.Errorf("%s.transforms.%d: %w", path, i, err)
+		}
+	}
+
+	if field.URL != nil {
+		if err := validateURL(field.URL); err != nil {
+			return fmt.Errorf("%s.url: %w", path, err)
+		}
+	}
+
+	if err := validateRounding(field.Rounding); err != nil {
+		return fmt.Errorf("%s.rounding: %w", path, err)
+	}
+
+	if field.Number != nil {
+		if err := validateNumber(field.Number); err != nil {
+			return fmt.Errorf("%s.number: %w", path, err)
+		}
+	}
+
+	if field.Regex != nil {
+		if _, _, err := compileRegexConfig(field.Regex); err != nil {
+			return fmt.Errorf("%s.regex: invalid regex %q: %w", path, field.Regex.Pattern, err)
+		}
+	}
+
+	for i, sub := range field.FirstOf {
+		if err := prepareBaseField(sub, fmt.Sprintf("%s.first_of.%d", path, i)); err != nil {
+			return err
+		}
+	}
+
+	if field.Generated == nil {
+		return nil
+	}
+
+	if field.Generated.Calculated != nil {
+		if err := prepareExpression(field.Generated.Calculated.Expression, path+".generated.calculated.expression"); err != nil {
+			return err
+		}
+	}
+
+	if field.Generated.Model != nil {
+		if err := prepareExpression(field.Generated.Model.Expression, path+".generated.model.expression"); err != nil {
+			return err
+		}
+		return PrepareModel(field.Generated.Model.Model, path+".generated.model.model")
+	}
+
+	return nil
+}
+
+func prepareField(field *config.Field, path string) error {
+	if field == nil {
+		return nil
+	}
+
+	for i, sub := range field.FirstOf {
+		if err := prepareField(sub, fmt.Sprintf("%s.first_of.%d", path, i)); err != nil {
+			return err
+		}
+	}
+
+	if err := prepareBaseField(field.BaseField, path+".base_field"); err != nil {
+		return err
+	}
+	if err := prepareObject(field.ObjectConfig, path+".object_config"); err != nil {
+		return err
+	}
+	return prepareArray(field.ArrayConfig, path+".array_config")
+}
+
+func prepareObject(object *config.ObjectConfig, path string) error {
+	if object == nil {
+		return nil
+	}
+
+	if err := prepareExpression(object.Condition, path+".condition"); err != nil {
+		return err
+	}
+	if err := prepareBaseField(object.Field, path+".field"); err != nil {
+		return err
+	}
+	for name, field := range object.Fields {
+		if err := prepareField(field, fmt.Sprintf("%s.fields.%s", path, name)); err != nil {
+			return err
+		}
+	}
+	return prepareArray(object.ArrayConfig, path+".array_config")
+}
+
+func prepareArray(array *config.ArrayConfig, path string) error {
+	if array == nil {
+		return nil
+	}
+
+	if err := prepareExpression(array.Condition, path+".condition"); err != nil {
+		return err
+	}
+	if err := prepareExpression(array.ItemCondition, path+".item_condition"); err != nil {
+		return err
+	}
+	if err := prepareObject(array.ItemConfig, path+".item_config"); err != nil {
+		return err
+	}
+	if array.Table != nil && array.Table.HeaderRows < 0 {
+		return fmt.Errorf("%s.table: negative header_rows %d", path, array.Table.HeaderRows)
+	}
+
+	if array.StaticConfig != nil {
+		for index, field := range array.StaticConfig.Items {
+			if err := prepareField(field, fmt.Sprintf("%s.static_array.items.%d", path, index)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package parser_test
+
+import (
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPrepareModel(t *testing.T) {
+	assert.NoError(t, parser.PrepareModel(&config.Model{
+		ArrayConfig: &config.ArrayConfig{
+			RootPath:      "@this",
+			ItemCondition: "fRes.price > {{{FromInput=min}}}",
+			ItemConfig: &config.ObjectConfig{
+				Fields: map[string]*config.Field{
+					"price": {
+						BaseField: &config.BaseField{
+							Type:      config.Float,
+							Path:      "price",
+							Condition: "fRes > 0",
+						},
+					},
+				},
+			},
+		},
+	}, "model"))
+
+	err := parser.PrepareModel(&config.Model{
+		ObjectConfig: &config.ObjectConfig{
+			Fields: map[string]*config.Field{
+				"total": {
+					BaseField: &config.BaseField{
+						Type: config.Float,
+						Generated: &config.GeneratedFieldConfig{
+							Calculated: &config.CalculatedConfig{
+								Type:       config.Float,
+								Expression: "fRes *** 2",
+							},
+						},
+					},
+				},
+			},
+		},
+	}, "item.model")
+	assert.ErrorContains(t, err, "item.model.object_config.fields.total.base_field.generated.calculated.expression")
+}
@@ -0,0 +1,319 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/tidwall/gjson"
+	"golang.org/x/net/html"
+)
+
+var (
+	unlikelyCandidates = regexp.MustCompile(`(?i)banner|breadcrumb|combx|comment|community|cookie|disqus|extra|footer|gdpr|header|menu|modal|nav|newsletter|pager|pagination|popup|promo|related|remark|replies|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|tags|tool|widget|ad-|ads`)
+	maybeCandidate     = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow|story|text|entry|post`)
+	positiveWeight     = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|post|text|blog|story`)
+	negativeWeight     = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+	bylineClass        = regexp.MustCompile(`(?i)byline|author|dateline|writtenby|p-author`)
+	titleSeparator     = regexp.MustCompile(`\s[|\-–—/»:]\s`)
+
+	readabilityRemoved = "script, style, noscript, template, iframe, button, input, select, textarea, svg, nav, aside, footer, object, embed"
+)
+
+type readabilityContent struct {
+	Title         string `json:"title"`
+	Byline        string `json:"byline"`
+	Excerpt       string `json:"excerpt"`
+	SiteName      string `json:"site_name"`
+	PublishedTime string `json:"published_time"`
+	Language      string `json:"lang"`
+	LeadImage     string `json:"lead_image"`
+	Content       string `json:"content"`
+	Text          string `json:"text"`
+	Markdown      string `json:"markdown"`
+	Length        int    `json:"length"`
+}
+
+// newReadability is the HTML parser with the readability option: it extracts
+// the main content of the article page (readability heuristics: text density,
+// commas, class names and link density) and exposes it as a JSON document
+// {"title", "byline", "excerpt", "site_name", "published_time", "lang",
+// "lead_image", "content", "text", "markdown", "length"}, so models address it
+// with regular gjson paths.
+func newReadability(body []byte, base urlBase, logger logger.Logger) *engineParser[*gjson.Result] {
+	document, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		logger.Errorw("unable to parse html for readability", "error", err.Error())
+		return NewJson(nil, logger)
+	}
+
+	base.href, _ = document.Find("base[href]").First().Attr("href")
+	jsonBody, err := json.Marshal(extractReadability(document, base))
+	if err != nil {
+		logger.Errorw("unable to marshal readability content", "error", err.Error())
+		return NewJson(nil, logger)
+	}
+
+	return NewJson(jsonBody, logger)
+}
+
+func extractReadability(document *goquery.Document, base urlBase) *readabilityContent {
+	meta := func(names ...string) string {
+		for _, name := range names {
+			selector := `meta[property="` + name + `"], meta[name="` + name + `"]`
+			if value := strings.TrimSpace(document.Find(selector).First().AttrOr("content", "")); value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+	resolve := func(link string) string {
+		if link == "" {
+			return ""
+		}
+		value, _ := urlValue(link, &config.BaseField{}, base).ToInterface().(string)
+		return value
+	}
+
+	content := &readabilityContent{
+		Title:         articleTitle(document, meta("og:title", "twitter:title")),
+		Byline:        articleByline(document, meta("author", "article:author", "dc.creator")),
+		Excerpt:       meta("og:description", "twitter:description", "description"),
+		SiteName:      meta("og:site_name"),
+		PublishedTime: meta("article:published_time", "date", "dc.date"),
+		Language:      document.Find("html").AttrOr("lang", ""),
+		LeadImage:     resolve(meta("og:image", "og:image:url", "twitter:image")),
+	}
+	if content.PublishedTime == "" {
+		content.PublishedTime = document.Find("time[datetime]").First().AttrOr("datetime", "")
+	}
+
+	document.Find(readabilityRemoved).Remove()
+	article := articleNodes(document)
+	if len(article) == 0 {
+		return content
+	}
+
+	var htmlContent, text strings.Builder
+	converter := &markdownConverter{base: base}
+	var markdown []string
+	for _, node := range article {
+		_ = html.Render(&htmlContent, node)
+		if part := converter.convert(node); part != "" {
+			markdown = append(markdown, part)
+		}
+	}
+	for _, node := range article {
+		text.WriteString(goquery.NewDocumentFromNode(node).Text())
+		text.WriteString("\n")
+	}
+
+	content.Content = htmlContent.String()
+	content.Markdown = strings.Join(markdown, "\n\n")
+	content.Text = articleText(text.String())
+	content.Length = utf8.RuneCountInString(content.Text)
+
+	if content.LeadImage == "" {
+		for _, node := range article {
+			if src, ok := goquery.NewDocumentFromNode(node).Find("img[src]").First().Attr("src"); ok {
+				content.LeadImage = resolve(src)
+				break
+			}
+		}
+	}
+	if content.Excerpt == "" {
+		for _, node := range article {
+			if paragraph := strings.TrimSpace(goquery.NewDocumentFromNode(node).Find("p").First().Text()); paragraph != "" {
+				content.Excerpt = strings.Join(strings.Fields(paragraph), " ")
+				break
+			}
+		}
+	}
+
+	return content
+}
+
+// articleTitle prefers the meta title, then <title> without the site name
+// ("Title | Site"), then the only <h1>
+func articleTitle(document *goquery.Document, metaTitle string) string {
+	if metaTitle != "" {
+		return metaTitle
+	}
+
+	title := strings.Join(strings.Fields(document.Find("title").First().Text()), " ")
+	if parts := titleSeparator.Split(title, -1); len(parts) > 1 && len(strings.Fields(parts[0])) >= 2 {
+		title = strings.TrimSpace(parts[0])
+	}
+	if headings := document.Find("h1"); title == "" && headings.Length() == 1 {
+		title = strings.Join(strings.Fields(headings.Text()), " ")
+	}
+	return title
+}
+
+func articleByline(document *goquery.Document, metaAuthor string) string {
+	if metaAuthor != "" {
+		return metaAuthor
+	}
+
+	var byline string
+	document.Find(`[rel="author"], [itemprop~="author"], [class], [id]`).EachWithBreak(func(_ int, selection *goquery.Selection) bool {
+		if _, rel := selection.Attr("rel"); !rel {
+			if _, prop := selection.Attr("itemprop"); !prop && !bylineClass.MatchString(selection.AttrOr("class", "")+" "+selection.AttrOr("id", "")) {
+				return true
+			}
+		}
+
+		text := strings.Join(strings.Fields(selection.Text()), " ")
+		if text != "" && utf8.RuneCountInString(text) < 100 {
+			byline = text
+			return false
+		}
+		return true
+	})
+	return byline
+}
+
+// articleText keeps the paragraphs of the text separated by an empty line
+func articleText(text string) string {
+	var paragraphs []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			paragraphs = append(paragraphs, line)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// articleNodes scores the parents of the paragraphs and returns the best
+// candidate with its related siblings
+func articleNodes(document *goquery.Document) []*html.Node {
+	body := document.Find("body")
+	if body.Length() == 0 {
+		return nil
+	}
+
+	body.Find("*").Each(func(_ int, selection *goquery.Selection) {
+		if selection.Is("article, main, body, html") {
+			return
+		}
+		match := selection.AttrOr("class", "") + " " + selection.AttrOr("id", "")
+		if unlikelyCandidates.MatchString(match) && !maybeCandidate.MatchString(match) && selection.Find("article, main").Length() == 0 {
+			selection.Remove()
+		}
+	})
+
+	scores := map[*html.Node]float64{}
+	var candidates []*html.Node
+	addScore := func(node *html.Node, score float64) {
+		if node == nil || node.Type != html.ElementNode {
+			return
+		}
+		if _, ok := scores[node]; !ok {
+			scores[node] = initialScore(node)
+			candidates = append(candidates, node)
+		}
+		scores[node] += score
+	}
+
+	body.Find("p, pre, td, blockquote, section > div, article > div").Each(func(_ int, selection *goquery.Selection) {
+		text := strings.TrimSpace(selection.Text())
+		length := utf8.RuneCountInString(text)
+		if length < 25 {
+			return
+		}
+
+		score := 1 + float64(strings.Count(text, ",")+strings.Count(text, "，")) + math.Min(float64(length)/100, 3)
+		node := selection.Nodes[0]
+		addScore(node.Parent, score)
+		if node.Parent != nil {
+			addScore(node.Parent.Parent, score/2)
+		}
+	})
+
+	var top *html.Node
+	for _, node := range candidates {
+		scores[node] *= 1 - linkDensity(node)
+		if top == nil || scores[node] > scores[top] {
+			top = node
+		}
+	}
+	if top == nil {
+		return []*html.Node{body.Nodes[0]}
+	}
+	if top.Data == "html" {
+		return []*html.Node{body.Nodes[0]}
+	}
+
+	threshold := math.Max(10, scores[top]*0.2)
+	var nodes []*html.Node
+	if top.Parent == nil {
+		return []*html.Node{top}
+	}
+	for sibling := top.Parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+		if sibling.Type != html.ElementNode {
+			continue
+		}
+		if sibling == top {
+			nodes = append(nodes, sibling)
+			continue
+		}
+		if score, ok := scores[sibling]; ok && score >= threshold {
+			nodes = append(nodes, sibling)
+			continue
+		}
+		if sibling.Data == "p" {
+			text := strings.TrimSpace(goquery.NewDocumentFromNode(sibling).Text())
+			if utf8.RuneCountInString(text) > 80 && linkDensity(sibling) < 0.25 {
+				nodes = append(nodes, sibling)
+			}
+		}
+	}
+
+	return nodes
+}
+
+func initialScore(node *html.Node) float64 {
+	var score float64
+	switch node.Data {
+	case "article", "main":
+		score = 10
+	case "div":
+		score = 5
+	case "pre", "td", "blockquote":
+		score = 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		score = -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score = -5
+	}
+
+	match := attr(node, "class") + " " + attr(node, "id")
+	if positiveWeight.MatchString(match) {
+		score += 25
+	}
+	if negativeWeight.MatchString(match) {
+		score -= 25
+	}
+	return score
+}
+
+// linkDensity is the part of the text inside links
+func linkDensity(node *html.Node) float64 {
+	selection := goquery.NewDocumentFromNode(node).Selection
+	length := utf8.RuneCountInString(strings.TrimSpace(selection.Text()))
+	if length == 0 {
+		return 0
+	}
+
+	var links int
+	selection.Find("a").Each(func(_ int, link *goquery.Selection) {
+		links += utf8.RuneCountInString(strings.TrimSpace(link.Text()))
+	})
+	return float64(links) / float64(length)
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+const articlePage = `<html lang="en"><head>
+	<title>Why Go is fun | The Blog</title>
+	<meta name="author" content="Jane Doe">
+	<meta property="article:published_time" content="2024-05-01T10:00:00Z">
+</head><body>
+	<nav class="menu"><a href="/">Home</a> <a href="/about">About</a></nav>
+	<div class="sidebar"><p>Subscribe to our newsletter, it is great, really, we promise, with many commas.</p></div>
+	<div id="main">
+		<div class="article-body">
+			<h1>Why Go is fun</h1>
+			<p>Go is a simple language, with a small spec, fast builds, and a great standard library.</p>
+			<p><img src="/img/gopher.png" alt="Gopher"></p>
+			<p>Concurrency is built in, with goroutines and channels, which makes servers easy to write.</p>
+			<p>Tooling is excellent: gofmt, go vet, go test, and the module system, all in one binary.</p>
+		</div>
+	</div>
+	<div class="comments"><p>First comment, nice article, thanks, I agree with everything here.</p></div>
+	<footer>Copyright</footer>
+</body></html>`
+
+func TestReadabilityParser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(articlePage))
+	}))
+	defer server.Close()
+
+	res, err := NewEngine(&config.ConnectorConfig{
+		ResponseType: config.HTML,
+		Readability:  true,
+		Url:          server.URL + "/posts/go",
+		ServerConfig: &config.ServerConnectorConfig{
+			Method: "GET",
+		},
+	}, logger.Null).Get(context.Background(), &config.Model{
+		BaseField: &config.BaseField{Type: config.RawString, Path: "@this"},
+	}, nil, nil, nil)
+	require.NoError(t, err)
+
+	article := gjson.Parse(gjson.ParseBytes(res.Raw()).String())
+	assert.Equal(t, "Why Go is fun", article.Get("title").String())
+	assert.Equal(t, "Jane Doe", article.Get("byline").String())
+	assert.Equal(t, "en", article.Get("lang").String())
+	assert.Equal(t, "2024-05-01T10:00:00Z", article.Get("published_time").String())
+	assert.Equal(t, server.URL+"/img/gopher.png", article.Get("lead_image").String())
+	assert.Equal(t, "Go is a simple language, with a small spec, fast builds, and a great standard library.", article.Get("excerpt").String())
+
+	text := article.Get("text").String()
+	assert.Contains(t, text, "Concurrency is built in")
+	assert.Contains(t, text, "Tooling is excellent")
+	assert.NotContains(t, text, "newsletter")
+	assert.NotContains(t, text, "First comment")
+	assert.NotContains(t, text, "Copyright")
+	assert.Equal(t, int64(len([]rune(text))), article.Get("length").Int())
+
+	markdown := article.Get("markdown").String()
+	assert.Contains(t, markdown, "# Why Go is fun\n\nGo is a simple language")
+	assert.Contains(t, markdown, "![Gopher]("+server.URL+"/img/gopher.png)")
+	assert.Contains(t, article.Get("content").String(), `class="article-body"`)
+}
+
+func TestReadabilityParserTitle(t *testing.T) {
+	res, err := newReadability([]byte(`<html><head><title>Site</title></head><body><h1>Only heading</h1></body></html>`), urlBase{}, logger.Null).Parse(&config.Model{
+		BaseField: &config.BaseField{Type: config.String, Path: "title"},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `"Site"`, res.ToJson())
+}
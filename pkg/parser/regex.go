@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+)
+
+var (
+	// regexCache keeps compiled patterns, fields are evaluated for every array item
+	regexCache sync.Map
+)
+
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := regexCache.Load(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	regexCache.Store(pattern, re)
+	return re, nil
+}
+
+// regexGroup resolves the capture group by name or index
+func regexGroup(re *regexp.Regexp, group string) (int, error) {
+	if group == "" {
+		return 0, nil
+	}
+	if index := re.SubexpIndex(group); index >= 0 {
+		return index, nil
+	}
+
+	index, err := strconv.Atoi(group)
+	if err != nil || index < 0 || index > re.NumSubexp() {
+		return 0, fmt.Errorf("unknown capture group %q", group)
+	}
+	return index, nil
+}
+
+func compileRegexConfig(cfg *config.RegexConfig) (*regexp.Regexp, int, error) {
+	re, err := compileRegex(cfg.Pattern)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	group, err := regexGroup(re, cfg.Group)
+	if err != nil {
+		return nil, 0, err
+	}
+	return re, group, nil
+}
+
+// regexValue converts the selected group (or the replace template) of the
+// first match, or of every match as array; no match gives null
+func regexValue(text string, cfg *config.RegexConfig, convert func(string) builder.Interfacable) builder.Interfacable {
+	re, group, err := compileRegexConfig(cfg)
+	if err != nil {
+		return builder.NullValue
+	}
+
+	if cfg.All {
+		matches := re.FindAllStringSubmatchIndex(text, -1)
+		values := make([]builder.Interfacable, len(matches))
+		for i, match := range matches {
+			values[i] = regexMatchValue(re, text, match, group, cfg.Replace, convert)
+		}
+		return builder.Array(values)
+	}
+
+	match := re.FindStringSubmatchIndex(text)
+	if match == nil {
+		return builder.NullValue
+	}
+	return regexMatchValue(re, text, match, group, cfg.Replace, convert)
+}
+
+func regexMatchValue(re *regexp.Regexp, text string, match []int, group int, replace string, convert func(string) builder.Interfacable) builder.Interfacable {
+	if replace != "" {
+		return convert(string(re.ExpandString(nil, replace, text, match)))
+	}
+
+	// optional group which didn't participate in the match
+	if match[2*group] < 0 {
+		return builder.NullValue
+	}
+	return convert(text[match[2*group]:match[2*group+1]])
+}
@@ -0,0 +1,75 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func regexModel(path string, attribute string) *config.Model {
+	field := func(fieldType config.FieldType, regex *config.RegexConfig) *config.Field {
+		return &config.Field{
+			BaseField: &config.BaseField{
+				Type:          fieldType,
+				Path:          path,
+				HTMLAttribute: attribute,
+				Regex:         regex,
+			},
+		}
+	}
+
+	return &config.Model{
+		ObjectConfig: &config.ObjectConfig{
+			Fields: map[string]*config.Field{
+				"euros":    field(config.Int, &config.RegexConfig{Pattern: `Price: (\d+)`, Group: "1"}),
+				"cents":    field(config.Int, &config.RegexConfig{Pattern: `,(?P<cents>\d+)`, Group: "cents"}),
+				"price":    field(config.Float, &config.RegexConfig{Pattern: `(\d+),(\d+)`, Replace: "$1.$2"}),
+				"currency": field(config.String, &config.RegexConfig{Pattern: `[€$]`}),
+				"numbers":  field(config.Int, &config.RegexConfig{Pattern: `\d+`, All: true}),
+				"missing":  field(config.String, &config.RegexConfig{Pattern: `USD`}),
+				"none":     field(config.String, &config.RegexConfig{Pattern: `USD`, All: true}),
+			},
+		},
+	}
+}
+
+const regexExpected = `{"euros": 1234, "cents": 50, "price": 1234.5, "currency": "€", "numbers": [1234, 50], "missing": null, "none": []}`
+
+func TestRegexField(t *testing.T) {
+	for name, tc := range map[string]struct {
+		parser parser.Parser
+		model  *config.Model
+	}{
+		"json":           {parser: parser.NewJson([]byte(`{"text": "Price: 1234,50 € incl. VAT"}`), logger.Null), model: regexModel("text", "")},
+		"html":           {parser: parser.NewHTML([]byte(`<p class="price">Price: 1234,50 € incl. VAT</p>`), logger.Null), model: regexModel(".price", "")},
+		"html attribute": {parser: parser.NewHTML([]byte(`<p class="price" title="Price: 1234,50 € incl. VAT"></p>`), logger.Null), model: regexModel(".price", "title")},
+		"xpath":          {parser: parser.NewXPath([]byte(`<p class="price">Price: 1234,50 € incl. VAT</p>`), logger.Null), model: regexModel("//p", "")},
+		"xml":            {parser: parser.NewXML([]byte(`<item><price>Price: 1234,50 € incl. VAT</price></item>`), logger.Null), model: regexModel("//price", "")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			res, err := tc.parser.Parse(tc.model, nil)
+			require.NoError(t, err)
+			assert.JSONEq(t, regexExpected, res.ToJson())
+		})
+	}
+}
+
+func TestRegexFieldPrepare(t *testing.T) {
+	for _, regex := range []*config.RegexConfig{
+		{Pattern: `(\d+`},
+		{Pattern: `(\d+)`, Group: "2"},
+		{Pattern: `(?P<price>\d+)`, Group: "cents"},
+	} {
+		err := parser.PrepareModel(&config.Model{
+			BaseField: &config.BaseField{
+				Type:  config.String,
+				Regex: regex,
+			},
+		}, "model")
+		assert.ErrorContains(t, err, "model.base_field.regex: invalid regex")
+	}
+}
@@ -0,0 +1,140 @@
+package parser_test
+
+import (
+	"fmt"
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/tidwall/gjson"
+	"strings"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	suite.Run(t, new(StreamSuite))
+}
+
+type StreamSuite struct {
+	suite.Suite
+	parser parser.Parser
+}
+
+func (s *StreamSuite) SetupTest() {
+	items := make([]string, 200)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"id": %d, "even": %t}`, i, i%2 == 0)
+	}
+	s.parser = parser.NewJson([]byte("["+strings.Join(items, ",")+"]"), logger.Null)
+}
+
+func (s *StreamSuite) collect(model *config.Model, limit int) []string {
+	var res []string
+	err := s.parser.Stream(model, nil, func(value builder.Interfacable) bool {
+		res = append(res, value.ToJson())
+		return limit <= 0 || len(res) < limit
+	})
+	require.NoError(s.T(), err)
+	return res
+}
+
+func (s *StreamSuite) Test_StreamMatchesParse() {
+	model := &config.Model{
+		ArrayConfig: &config.ArrayConfig{
+			RootPath:      "@this",
+			ItemCondition: "fSrc.even",
+			ItemConfig: &config.ObjectConfig{
+				Field: &config.BaseField{
+					Type: config.Int,
+					Path: "id",
+				},
+			},
+		},
+	}
+
+	res, err := s.parser.Parse(model, nil)
+	require.NoError(s.T(), err)
+
+	assert.Equal(s.T(), res.ToJson(), "["+strings.Join(s.collect(model, 0), ",")+"]")
+}
+
+func (s *StreamSuite) Test_StreamLengthLimitAndReverse() {
+	assert.Equal(s.T(), []string{"199", "198", "197"}, s.collect(&config.Model{
+		ArrayConfig: &config.ArrayConfig{
+			RootPath:    "@this",
+			Reverse:     true,
+			LengthLimit: 3,
+			ItemConfig: &config.ObjectConfig{
+				Field: &config.BaseField{
+					Type: config.Int,
+					Path: "id",
+				},
+			},
+		},
+	}, 0))
+}
+
+func (s *StreamSuite) Test_StreamStopsWhenEmitReturnsFalse() {
+	assert.Equal(s.T(), []string{"0", "1", "2", "3", "4"}, s.collect(&config.Model{
+		ArrayConfig: &config.ArrayConfig{
+			RootPath: "@this",
+			ItemConfig: &config.ObjectConfig{
+				Field: &config.BaseField{
+					Type: config.Int,
+					Path: "id",
+				},
+			},
+		},
+	}, 5))
+}
+
+func (s *StreamSuite) Test_StreamObjectModelEmitsOnce() {
+	assert.Equal(s.T(), []string{"7"}, s.collect(&config.Model{
+		BaseField: &config.BaseField{
+			Type: config.Int,
+			Path: "7.id",
+		},
+	}, 0))
+}
+
+func TestFillArrayWrappers(t *testing.T) {
+	body := gjson.Parse(`[{"id": 1, "tags": ["a", "b"]}, {"id": 2, "tags": ["c"]}, {"id": 3, "tags": []}]`)
+	engine := parser.NewJson([]byte(body.Raw), logger.Null)
+	parent := make([]*gjson.Result, 0, 3)
+	for _, item := range body.Array() {
+		item := item
+		parent = append(parent, &item)
+	}
+
+	res := parser.FillArrayBaseField(engine, parent, 2, &config.ArrayConfig{
+		ItemConfig: &config.ObjectConfig{Field: &config.BaseField{Type: config.Int, Path: "id"}},
+	}, nil)
+	assert.JSONEq(t, `[1, 2]`, res.ToJson())
+
+	res = parser.FillArrayObjectField(engine, parent, 3, &config.ArrayConfig{
+		ItemCondition: "fRes.id != 2",
+		ItemConfig: &config.ObjectConfig{Fields: map[string]*config.Field{
+			"id": {BaseField: &config.BaseField{Type: config.Int, Path: "id"}},
+		}},
+	}, nil)
+	assert.JSONEq(t, `[{"id": 1}, {"id": 3}]`, res.ToJson())
+
+	getAll := func(parent *gjson.Result, path string) []*gjson.Result {
+		var values []*gjson.Result
+		for _, value := range parent.Get(path).Array() {
+			value := value
+			values = append(values, &value)
+		}
+		return values
+	}
+	res = parser.FillArrayArrayField(engine, parent, 3, getAll, &config.ArrayConfig{
+		ItemConfig: &config.ObjectConfig{ArrayConfig: &config.ArrayConfig{
+			RootPath:   "tags",
+			ItemConfig: &config.ObjectConfig{Field: &config.BaseField{Type: config.String, Path: "@this"}},
+		}},
+	}, nil)
+	assert.JSONEq(t, `[["a", "b"], ["c"], []]`, res.ToJson())
+}
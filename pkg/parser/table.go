@@ -0,0 +1,271 @@
+package parser
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/PxyUp/fitter/pkg/config"
+	"golang.org/x/net/html"
+)
+
+const (
+	// maxTableColspan and maxTableRowspan are the limits of the HTML spec
+	maxTableColspan = 1000
+	maxTableRowspan = 65534
+	// maxTableCells caps the grid area (rows * widest row), the rows which
+	// don't fit are dropped
+	maxTableCells = 1000000
+)
+
+type tableCell struct {
+	text   string
+	header bool
+}
+
+// tableRows converts the table into json rows: objects keyed by the header
+// text or arrays of cell texts. colspan/rowspan are expanded, so a spanned
+// cell repeats its text in every covered slot
+func tableRows(table *html.Node, cfg *config.TableConfig) []byte {
+	table = findTable(table)
+	if table == nil {
+		return []byte("[]")
+	}
+
+	grid, headRows := tableGrid(table, cellText)
+
+	headerRows := cfg.HeaderRows
+	if headerRows == 0 {
+		headerRows = detectHeaderRows(grid, headRows)
+	}
+	if cfg.NoHeader {
+		headerRows = 0
+	}
+	headerRows = min(headerRows, len(grid))
+
+	var rows []interface{}
+	var keys []string
+	if !cfg.AsArrays {
+		keys = tableKeys(grid[:headerRows], cfg.HeaderSeparator)
+	}
+
+	// short rows are padded to the widest one
+	width := 0
+	for _, cells := range grid {
+		width = max(width, len(cells))
+	}
+
+	for _, cells := range grid[headerRows:] {
+		values := make([]string, width)
+		empty := true
+		for i, cell := range cells {
+			if cell != nil {
+				values[i] = cell.text
+			}
+			empty = empty && values[i] == ""
+		}
+		if empty {
+			continue
+		}
+
+		if cfg.AsArrays {
+			rows = append(rows, values)
+			continue
+		}
+
+		row := make(map[string]string, len(values))
+		for i, value := range values {
+			key := strconv.Itoa(i)
+			if i < len(keys) {
+				key = keys[i]
+			}
+			row[key] = value
+		}
+		rows = append(rows, row)
+	}
+
+	if rows == nil {
+		return []byte("[]")
+	}
+	body, _ := json.Marshal(rows)
+	return body
+}
+
+// findTable returns the node itself or its first descendant <table>
+func findTable(node *html.Node) *html.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Type == html.ElementNode && node.Data == "table" {
+		return node
+	}
+
+	tables := goquery.NewDocumentFromNode(node).Find("table")
+	if tables.Length() == 0 {
+		return nil
+	}
+	return tables.Nodes[0]
+}
+
+func cellText(cell *html.Node) string {
+	return strings.Join(strings.Fields(goquery.NewDocumentFromNode(cell).Text()), " ")
+}
+
+// tableGrid places the cells of the own rows of the table (nested tables are
+// skipped) on the grid; headRows is the amount of rows from <thead>
+func tableGrid(table *html.Node, text func(*html.Node) string) ([][]*tableCell, int) {
+	var rows []*html.Node
+	headRows := 0
+	for child := table.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		switch child.Data {
+		case "tr":
+			rows = append(rows, child)
+		case "thead", "tbody", "tfoot":
+			for row := child.FirstChild; row != nil; row = row.NextSibling {
+				if row.Type == html.ElementNode && row.Data == "tr" {
+					rows = append(rows, row)
+					if child.Data == "thead" {
+						headRows++
+					}
+				}
+			}
+		}
+	}
+
+	grid := make([][]*tableCell, len(rows))
+	// width and height of the filled part of the grid
+	width, height := 0, 0
+	for r, row := range rows {
+		if (r+1)*width > maxTableCells {
+			return grid[:r], min(headRows, r)
+		}
+		height = max(height, r+1)
+
+		column := 0
+		for cell := row.FirstChild; cell != nil; cell = cell.NextSibling {
+			if cell.Type != html.ElementNode || (cell.Data != "td" && cell.Data != "th") {
+				continue
+			}
+
+			// skip the slots taken by rowspan of the rows above
+			for column < len(grid[r]) && grid[r][column] != nil {
+				column++
+			}
+
+			value := &tableCell{
+				text:   text(cell),
+				header: cell.Data == "th",
+			}
+			colspan := tableSpan(cell, "colspan", maxTableColspan)
+			rowspan := tableSpan(cell, "rowspan", maxTableRowspan)
+			if span, ok := attrValue(cell, "rowspan"); ok && strings.TrimSpace(span) == "0" {
+				// rowspan=0 spans to the end of the table
+				rowspan = len(rows) - r
+			}
+			rowspan = min(rowspan, len(rows)-r)
+
+			newWidth := max(width, column+colspan)
+			if height*newWidth > maxTableCells {
+				// the rest of the row and the rows below are dropped
+				return grid[:r+1], min(headRows, r+1)
+			}
+			// the span is clipped to the rows which fit, the row loop drops the rest
+			rowspan = min(rowspan, maxTableCells/newWidth-r)
+			width = newWidth
+			height = max(height, r+rowspan)
+
+			for dr := 0; dr < rowspan; dr++ {
+				for dc := 0; dc < colspan; dc++ {
+					setCell(&grid[r+dr], column+dc, value)
+				}
+			}
+			column += colspan
+		}
+	}
+
+	return grid, headRows
+}
+
+func tableSpan(cell *html.Node, name string, limit int) int {
+	span, err := strconv.Atoi(strings.TrimSpace(attr(cell, name)))
+	if err != nil || span < 1 {
+		return 1
+	}
+	return min(span, limit)
+}
+
+func setCell(row *[]*tableCell, column int, cell *tableCell) {
+	for len(*row) <= column {
+		*row = append(*row, nil)
+	}
+	if (*row)[column] == nil {
+		(*row)[column] = cell
+	}
+}
+
+// detectHeaderRows uses <thead>, otherwise the leading rows made of <th> only
+func detectHeaderRows(grid [][]*tableCell, headRows int) int {
+	if headRows > 0 {
+		return headRows
+	}
+
+	rows := 0
+	for _, cells := range grid {
+		for _, cell := range cells {
+			if cell == nil || !cell.header {
+				return rows
+			}
+		}
+		if len(cells) == 0 {
+			return rows
+		}
+		rows++
+	}
+	return rows
+}
+
+// tableKeys joins the header texts of every column from top to bottom, the
+// same text spanned over several rows is used once; empty keys fall back to
+// the column index and duplicates get a suffix
+func tableKeys(header [][]*tableCell, separator string) []string {
+	if separator == "" {
+		separator = " "
+	}
+
+	columns := 0
+	for _, cells := range header {
+		columns = max(columns, len(cells))
+	}
+
+	keys := make([]string, columns)
+	seen := make(map[string]int, columns)
+	for column := range keys {
+		var parts []string
+		var previous *tableCell
+		for _, cells := range header {
+			if column >= len(cells) || cells[column] == nil || cells[column] == previous {
+				continue
+			}
+			previous = cells[column]
+			if cells[column].text != "" {
+				parts = append(parts, cells[column].text)
+			}
+		}
+
+		key := strings.Join(parts, separator)
+		if key == "" {
+			key = strconv.Itoa(column)
+		}
+		seen[key]++
+		if seen[key] > 1 {
+			key += "_" + strconv.Itoa(seen[key])
+		}
+		keys[column] = key
+	}
+
+	return keys
+}
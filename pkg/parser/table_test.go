@@ -0,0 +1,114 @@
+package parser_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+const tablePage = `<html><body><table id="prices">
+	<thead>
+		<tr><th rowspan="2">Name</th><th colspan="2">Price</th><th rowspan="2"></th></tr>
+		<tr><th>Min</th><th>Max</th></tr>
+	</thead>
+	<tbody>
+		<tr><td rowspan="2">Apple</td><td>1</td><td>2</td><td>fresh</td></tr>
+		<tr><td colspan="2">3</td><td><table><tr><td>nested</td></tr></table></td></tr>
+		<tr><td></td><td></td></tr>
+		<tr><td>Pear</td><td>4</td></tr>
+	</tbody>
+</table></body></html>`
+
+func tableModel(path string, table *config.TableConfig, item *config.ObjectConfig) *config.Model {
+	if item == nil {
+		item = &config.ObjectConfig{
+			Field: &config.BaseField{Type: config.Object, Path: "@this"},
+		}
+	}
+	return &config.Model{
+		ArrayConfig: &config.ArrayConfig{
+			RootPath:   path,
+			Table:      table,
+			ItemConfig: item,
+		},
+	}
+}
+
+func TestTableArray(t *testing.T) {
+	for name, tc := range map[string]struct {
+		parser parser.Parser
+		path   string
+	}{
+		"html":  {parser: parser.NewHTML([]byte(tablePage), logger.Null), path: "#prices"},
+		"xpath": {parser: parser.NewXPath([]byte(tablePage), logger.Null), path: "//table[@id='prices']"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			res, err := tc.parser.Parse(tableModel(tc.path, &config.TableConfig{}, nil), nil)
+			require.NoError(t, err)
+			assert.JSONEq(t, `[
+				{"Name": "Apple", "Price Min": "1", "Price Max": "2", "3": "fresh"},
+				{"Name": "Apple", "Price Min": "3", "Price Max": "3", "3": "nested"},
+				{"Name": "Pear", "Price Min": "4", "Price Max": "", "3": ""}
+			]`, res.ToJson())
+		})
+	}
+}
+
+func TestTableArrayItemConfig(t *testing.T) {
+	res, err := parser.NewHTML([]byte(tablePage), logger.Null).Parse(tableModel("#prices", &config.TableConfig{HeaderSeparator: "_"}, &config.ObjectConfig{
+		Fields: map[string]*config.Field{
+			"name": {BaseField: &config.BaseField{Type: config.String, Path: "Name"}},
+			"min":  {BaseField: &config.BaseField{Type: config.Int, Path: "Price_Min"}},
+		},
+	}), nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"name": "Apple", "min": 1}, {"name": "Apple", "min": 3}, {"name": "Pear", "min": 4}]`, res.ToJson())
+}
+
+func TestTableArrayAsArrays(t *testing.T) {
+	body := []byte(`<table><tr><th>A</th><th>B</th></tr><tr><td>1</td><td>2</td></tr></table>`)
+	for _, tc := range []struct {
+		table    *config.TableConfig
+		expected string
+	}{
+		{table: &config.TableConfig{AsArrays: true}, expected: `[["1", "2"]]`},
+		{table: &config.TableConfig{NoHeader: true}, expected: `[{"0": "A", "1": "B"}, {"0": "1", "1": "2"}]`},
+		{table: &config.TableConfig{HeaderRows: 2}, expected: `[]`},
+	} {
+		res, err := parser.NewHTML(body, logger.Null).Parse(tableModel("table", tc.table, nil), nil)
+		require.NoError(t, err)
+		assert.JSONEq(t, tc.expected, res.ToJson())
+	}
+}
+
+func TestTableArrayStream(t *testing.T) {
+	var items []string
+	err := parser.NewHTML([]byte(tablePage), logger.Null).Stream(tableModel("#prices", &config.TableConfig{}, &config.ObjectConfig{
+		Field: &config.BaseField{Type: config.String, Path: "Name"},
+	}), nil, func(item builder.Interfacable) bool {
+		items = append(items, item.ToJson())
+		return true
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{`"Apple"`, `"Apple"`, `"Pear"`}, items)
+}
+
+func TestTableArraySpanLimits(t *testing.T) {
+	// a single cell may span 1000 x 65534 slots, the grid area is capped instead
+	body := `<table><tr><td colspan="5000" rowspan="100000">x</td></tr>` + strings.Repeat(`<tr></tr>`, 1999) + `</table>`
+
+	res, err := parser.NewHTML([]byte(body), logger.Null).Parse(tableModel("table", &config.TableConfig{AsArrays: true}, nil), nil)
+	require.NoError(t, err)
+
+	rows := gjson.Parse(res.ToJson()).Array()
+	require.Len(t, rows, 1000)
+	assert.Len(t, rows[0].Array(), 1000)
+	assert.Equal(t, "x", rows[999].Array()[999].String())
+}
@@ -0,0 +1,207 @@
+package parser
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+	"golang.org/x/net/html"
+)
+
+var (
+	errBase64 = errors.New("invalid base64")
+
+	base64Encodings = []*base64.Encoding{
+		base64.StdEncoding,
+		base64.RawStdEncoding,
+		base64.URLEncoding,
+		base64.RawURLEncoding,
+	}
+)
+
+// validateTransform checks the step at config load time
+func validateTransform(transform *config.TransformConfig) error {
+	if transform == nil {
+		return errors.New("empty transform")
+	}
+
+	switch transform.Type {
+	case config.TransformTrim, config.TransformLower, config.TransformUpper, config.TransformCollapseWhitespace,
+		config.TransformStripTags, config.TransformJoin, config.TransformHTMLUnescape, config.TransformURLDecode,
+		config.TransformBase64Decode, config.TransformDefault:
+	case config.TransformReplace:
+		if transform.Pattern == "" {
+			return errors.New("replace requires pattern")
+		}
+	case config.TransformRegexReplace:
+		if _, err := compileRegex(transform.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", transform.Pattern, err)
+		}
+	case config.TransformSplit:
+		if transform.Separator == "" {
+			return errors.New("split requires separator")
+		}
+	case config.TransformSubstring:
+		if transform.Start >= 0 && transform.End > 0 && transform.End < transform.Start {
+			return fmt.Errorf("substring end %d is before start %d", transform.End, transform.Start)
+		}
+	default:
+		return fmt.Errorf("unknown transform %q", transform.Type)
+	}
+
+	return nil
+}
+
+// baseFieldValue runs the transforms and the regex of the field on the
+// extracted text and converts the result to the field type; after split the
+// value is a list and every item is converted
+func baseFieldValue(text string, field *config.BaseField, convert func(string) builder.Interfacable) builder.Interfacable {
+	values, list, err := applyTransforms([]string{text}, false, field.Transforms)
+	if err != nil {
+		return builder.NullValue
+	}
+
+	value := func(text string) builder.Interfacable {
+		if field.Regex != nil {
+			return regexValue(text, field.Regex, convert)
+		}
+		return convert(text)
+	}
+
+	if !list {
+		return value(values[0])
+	}
+
+	items := make([]builder.Interfacable, len(values))
+	for i, item := range values {
+		items[i] = value(item)
+	}
+	return builder.Array(items)
+}
+
+// missingValue is the value of the field which wasn't found, null unless a
+// default step fills it
+func missingValue(field *config.BaseField, convert func(string) builder.Interfacable) builder.Interfacable {
+	for _, transform := range field.Transforms {
+		if transform != nil && transform.Type == config.TransformDefault {
+			return baseFieldValue("", field, convert)
+		}
+	}
+	return builder.NullValue
+}
+
+func applyTransforms(values []string, list bool, transforms []*config.TransformConfig) ([]string, bool, error) {
+	for _, transform := range transforms {
+		if transform == nil {
+			continue
+		}
+
+		switch transform.Type {
+		case config.TransformSplit:
+			var parts []string
+			for _, value := range values {
+				parts = append(parts, strings.Split(value, transform.Separator)...)
+			}
+			values, list = parts, true
+		case config.TransformJoin:
+			values, list = []string{strings.Join(values, transform.Separator)}, false
+		default:
+			for i, value := range values {
+				transformed, err := applyTransform(value, transform)
+				if err != nil {
+					return nil, false, err
+				}
+				values[i] = transformed
+			}
+		}
+	}
+
+	return values, list, nil
+}
+
+func applyTransform(value string, transform *config.TransformConfig) (string, error) {
+	switch transform.Type {
+	case config.TransformTrim:
+		return strings.TrimSpace(value), nil
+	case config.TransformLower:
+		return strings.ToLower(value), nil
+	case config.TransformUpper:
+		return strings.ToUpper(value), nil
+	case config.TransformCollapseWhitespace:
+		return strings.Join(strings.Fields(value), " "), nil
+	case config.TransformStripTags:
+		return stripTags(value), nil
+	case config.TransformReplace:
+		return strings.ReplaceAll(value, transform.Pattern, transform.Replacement), nil
+	case config.TransformRegexReplace:
+		re, err := compileRegex(transform.Pattern)
+		if err != nil {
+			return "", err
+		}
+		return re.ReplaceAllString(value, transform.Replacement), nil
+	case config.TransformHTMLUnescape:
+		return html.UnescapeString(value), nil
+	case config.TransformURLDecode:
+		return url.QueryUnescape(value)
+	case config.TransformBase64Decode:
+		return base64Decode(value)
+	case config.TransformSubstring:
+		return substring(value, transform.Start, transform.End), nil
+	case config.TransformDefault:
+		if value == "" {
+			return transform.Value, nil
+		}
+		return value, nil
+	}
+
+	return "", fmt.Errorf("unknown transform %q", transform.Type)
+}
+
+// stripTags keeps the text between the tags as is, entities stay escaped
+func stripTags(value string) string {
+	var text strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(value))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return text.String()
+		case html.TextToken:
+			text.Write(tokenizer.Raw())
+		}
+	}
+}
+
+func base64Decode(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	for _, encoding := range base64Encodings {
+		decoded, err := encoding.DecodeString(value)
+		if err == nil {
+			return string(decoded), nil
+		}
+	}
+	return "", errBase64
+}
+
+// substring cuts by characters, negative positions count from the end
+func substring(value string, start int, end int) string {
+	runes := []rune(value)
+	position := func(index int) int {
+		if index < 0 {
+			index += len(runes)
+		}
+		return max(0, min(index, len(runes)))
+	}
+
+	from, to := position(start), len(runes)
+	if end != 0 {
+		to = position(end)
+	}
+	if to <= from {
+		return ""
+	}
+	return string(runes[from:to])
+}
@@ -0,0 +1,113 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransforms(t *testing.T) {
+	body := []byte(`{
+		"title": "  Hello   <b>Big</b>\n World  ",
+		"tags": "Go, Rust ,zig",
+		"encoded": "a%20b%2Bc",
+		"b64": "Zml0dGVy",
+		"escaped": "Tom &amp; Jerry",
+		"price": "USD 1,234.50",
+		"sku": "SKU-000123-XL"
+	}`)
+
+	field := func(fieldType config.FieldType, path string, transforms ...*config.TransformConfig) *config.Field {
+		return &config.Field{
+			BaseField: &config.BaseField{
+				Type:       fieldType,
+				Path:       path,
+				Transforms: transforms,
+			},
+		}
+	}
+
+	res, err := parser.NewJson(body, logger.Null).Parse(&config.Model{
+		ObjectConfig: &config.ObjectConfig{
+			Fields: map[string]*config.Field{
+				"title": field(config.RawString, "title",
+					&config.TransformConfig{Type: config.TransformStripTags},
+					&config.TransformConfig{Type: config.TransformCollapseWhitespace},
+					&config.TransformConfig{Type: config.TransformUpper},
+				),
+				"tags": field(config.String, "tags",
+					&config.TransformConfig{Type: config.TransformSplit, Separator: ","},
+					&config.TransformConfig{Type: config.TransformTrim},
+					&config.TransformConfig{Type: config.TransformLower},
+				),
+				"joined": field(config.String, "tags",
+					&config.TransformConfig{Type: config.TransformSplit, Separator: ","},
+					&config.TransformConfig{Type: config.TransformTrim},
+					&config.TransformConfig{Type: config.TransformJoin, Separator: "|"},
+				),
+				"decoded": field(config.String, "encoded", &config.TransformConfig{Type: config.TransformURLDecode}),
+				"b64":     field(config.String, "b64", &config.TransformConfig{Type: config.TransformBase64Decode}),
+				"escaped": field(config.String, "escaped", &config.TransformConfig{Type: config.TransformHTMLUnescape}),
+				"price": field(config.Float, "price",
+					&config.TransformConfig{Type: config.TransformReplace, Pattern: ",", Replacement: ""},
+					&config.TransformConfig{Type: config.TransformRegexReplace, Pattern: `[^\d.]`},
+				),
+				"size":    field(config.String, "sku", &config.TransformConfig{Type: config.TransformSubstring, Start: -2}),
+				"number":  field(config.Int, "sku", &config.TransformConfig{Type: config.TransformSubstring, Start: 4, End: 10}),
+				"missing": field(config.String, "unknown", &config.TransformConfig{Type: config.TransformDefault, Value: "n/a"}),
+				"invalid": field(config.String, "b64", &config.TransformConfig{Type: config.TransformURLDecode}, &config.TransformConfig{Type: config.TransformSubstring, Start: 100}, &config.TransformConfig{Type: config.TransformBase64Decode}),
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"title": "HELLO BIG WORLD",
+		"tags": ["go", "rust", "zig"],
+		"joined": "Go|Rust|zig",
+		"decoded": "a b+c",
+		"b64": "fitter",
+		"escaped": "Tom & Jerry",
+		"price": 1234.5,
+		"size": "XL",
+		"number": 123,
+		"missing": "n/a",
+		"invalid": ""
+	}`, string(res.Raw()))
+}
+
+func TestTransformsHTMLDefault(t *testing.T) {
+	res, err := parser.NewHTML([]byte(`<a class="link">Home</a>`), logger.Null).Parse(&config.Model{
+		BaseField: &config.BaseField{
+			Type:          config.String,
+			Path:          ".link",
+			HTMLAttribute: "href",
+			Transforms: []*config.TransformConfig{
+				{Type: config.TransformDefault, Value: "/"},
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `"/"`, res.ToJson())
+}
+
+func TestTransformsPrepare(t *testing.T) {
+	for _, transform := range []*config.TransformConfig{
+		{Type: "reverse"},
+		{Type: config.TransformReplace},
+		{Type: config.TransformRegexReplace, Pattern: `(`},
+		{Type: config.TransformSplit},
+		{Type: config.TransformSubstring, Start: 5, End: 2},
+	} {
+		err := parser.PrepareModel(&config.Model{
+			BaseField: &config.BaseField{
+				Type:       config.String,
+				Transforms: []*config.TransformConfig{{Type: config.TransformTrim}, transform},
+			},
+		}, "model")
+		assert.ErrorContains(t, err, "model.base_field.transforms.1")
+	}
+}
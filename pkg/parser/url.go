@@ -0,0 +1,140 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+)
+
+var (
+	trackingParams = map[string]bool{
+		"fbclid": true, "gclid": true, "dclid": true, "gbraid": true, "wbraid": true, "msclkid": true,
+		"yclid": true, "mc_cid": true, "mc_eid": true, "igshid": true, "_ga": true, "_gl": true,
+		"_hsenc": true, "_hsmi": true, "mkt_tok": true, "ref_src": true,
+	}
+)
+
+type pageURLKey struct{}
+
+// withPageURL passes the url reported by the connector to the parser
+func withPageURL(ctx context.Context, pageURL string) context.Context {
+	return context.WithValue(ctx, pageURLKey{}, pageURL)
+}
+
+// urlBase is what the url type is resolved against: the page url and <base href> of the document
+type urlBase struct {
+	page string
+	href string
+}
+
+// resolve returns the absolute base, nil when it is unknown
+func (b urlBase) resolve(field *config.BaseField) *url.URL {
+	page := b.page
+	if page == "" && field.URL != nil {
+		page = field.URL.Base
+	}
+
+	base, err := url.Parse(page)
+	if err != nil {
+		base = &url.URL{}
+	}
+	if b.href != "" {
+		if href, errHref := url.Parse(strings.TrimSpace(b.href)); errHref == nil {
+			base = base.ResolveReference(href)
+		}
+	}
+
+	if !base.IsAbs() {
+		return nil
+	}
+	return base
+}
+
+// validateURL checks the url settings at config load time
+func validateURL(cfg *config.URLConfig) error {
+	if cfg.Base == "" {
+		return nil
+	}
+
+	base, err := url.Parse(cfg.Base)
+	if err != nil || !base.IsAbs() {
+		return fmt.Errorf("base %q is not an absolute url", cfg.Base)
+	}
+	return nil
+}
+
+// urlValue resolves relative, protocol-relative and dot-segment links and
+// normalizes the result
+func urlValue(text string, field *config.BaseField, base urlBase) builder.Interfacable {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return builder.NullValue
+	}
+
+	link, err := url.Parse(text)
+	if err != nil {
+		return builder.NullValue
+	}
+	if resolved := base.resolve(field); resolved != nil {
+		link = resolved.ResolveReference(link)
+	}
+
+	normalizeURL(link)
+	if field.URL != nil {
+		stripParams(link, field.URL)
+	}
+	return builder.String(link.String(), false)
+}
+
+// normalizeURL lowercases scheme and host and drops the default port
+func normalizeURL(link *url.URL) {
+	link.Scheme = strings.ToLower(link.Scheme)
+	link.Host = strings.ToLower(link.Host)
+
+	port := link.Port()
+	if (link.Scheme == "http" && port == "80") || (link.Scheme == "https" && port == "443") {
+		link.Host = strings.TrimSuffix(link.Host, ":"+port)
+	}
+	if link.Host != "" && link.Path == "" && link.Opaque == "" {
+		link.Path = "/"
+	}
+}
+
+// stripParams removes the query params keeping the order of the rest
+func stripParams(link *url.URL, cfg *config.URLConfig) {
+	if link.RawQuery == "" || (!cfg.StripTracking && len(cfg.StripParams) == 0) {
+		return
+	}
+
+	parts := strings.Split(link.RawQuery, "&")
+	kept := parts[:0]
+	for _, part := range parts {
+		name, _, _ := strings.Cut(part, "=")
+		if decoded, err := url.QueryUnescape(name); err == nil {
+			name = decoded
+		}
+		if part == "" || stripParam(name, cfg) {
+			continue
+		}
+		kept = append(kept, part)
+	}
+	link.RawQuery = strings.Join(kept, "&")
+}
+
+func stripParam(name string, cfg *config.URLConfig) bool {
+	for _, param := range cfg.StripParams {
+		if param == name {
+			return true
+		}
+	}
+
+	if !cfg.StripTracking {
+		return false
+	}
+	lower := strings.ToLower(name)
+	return strings.HasPrefix(lower, "utm_") || trackingParams[lower]
+}
@@ -0,0 +1,102 @@
+package parser_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func urlModel(path string, attribute string, cfg *config.URLConfig) *config.Model {
+	return &config.Model{
+		ArrayConfig: &config.ArrayConfig{
+			RootPath: path,
+			ItemConfig: &config.ObjectConfig{
+				Field: &config.BaseField{
+					Type:          config.URL,
+					HTMLAttribute: attribute,
+					URL:           cfg,
+				},
+			},
+		},
+	}
+}
+
+func TestURLFieldFinalURL(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/catalog/page/", http.StatusFound)
+	})
+	mux.HandleFunc("/catalog/page/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body>
+			<a href="/item/42">a</a>
+			<a href="../img.png">b</a>
+			<a href="//cdn.example.com/x.js">c</a>
+			<a href="HTTPS://Example.COM:443/p?utm_source=x&id=1&fbclid=y#top">d</a>
+			<a href="">e</a>
+		</body></html>`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	res, err := parser.NewEngine(&config.ConnectorConfig{
+		ResponseType: config.HTML,
+		Url:          server.URL + "/old",
+		ServerConfig: &config.ServerConnectorConfig{
+			Method: "GET",
+		},
+	}, logger.Null).Get(context.Background(), urlModel("a", "href", &config.URLConfig{StripTracking: true}), nil, nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		"`+server.URL+`/item/42",
+		"`+server.URL+`/catalog/img.png",
+		"http://cdn.example.com/x.js",
+		"https://example.com/p?id=1#top",
+		null
+	]`, string(res.Raw()))
+}
+
+func TestURLFieldBaseTag(t *testing.T) {
+	body := []byte(`<html><head><base href="https://shop.example.com/en/"></head><body>
+		<img src="img/1.png"><img src="/2.png">
+	</body></html>`)
+	cfg := &config.URLConfig{Base: "https://example.com/page"}
+
+	for name, p := range map[string]parser.Parser{
+		"html":  parser.NewHTML(body, logger.Null),
+		"xpath": parser.NewXPath(body, logger.Null),
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := "img"
+			if name == "xpath" {
+				path = "//img/@src"
+			}
+			res, err := p.Parse(urlModel(path, "src", cfg), nil)
+			require.NoError(t, err)
+			assert.JSONEq(t, `["https://shop.example.com/en/img/1.png", "https://shop.example.com/2.png"]`, string(res.Raw()))
+		})
+	}
+}
+
+func TestURLFieldJson(t *testing.T) {
+	body := []byte(`{"links": ["/a?ref=1&session=2", "https://other.org"]}`)
+	res, err := parser.NewJson(body, logger.Null).Parse(urlModel("links", "", &config.URLConfig{
+		Base:        "https://example.com/list",
+		StripParams: []string{"session"},
+	}), nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `["https://example.com/a?ref=1", "https://other.org/"]`, string(res.Raw()))
+}
+
+func TestURLFieldPrepare(t *testing.T) {
+	err := parser.PrepareModel(&config.Model{
+		BaseField: &config.BaseField{Type: config.URL, URL: &config.URLConfig{Base: "/relative"}},
+	}, "model")
+	assert.ErrorContains(t, err, "model.base_field.url")
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"github.com/PxyUp/fitter/pkg/limitter"
+	"sync"
+)
+
+// resolveConcurrently runs task for every index in [0, count) and waits for
+// all of them, respecting the field worker limits: in sequential mode tasks
+// run one by one in the caller goroutine; otherwise at most
+// FieldWorkersPerItem tasks of this call run at once, and when the global
+// FieldWorkers pool is exhausted the task runs inline instead of waiting for
+// a free worker. Nested objects and generated models hold a worker while
+// waiting for their own fields, so blocking on the global pool could
+// deadlock; running inline cannot and still bounds the amount of goroutines
+func resolveConcurrently(count int, task func(int)) {
+	if limitter.SequentialFields() {
+		for i := 0; i < count; i++ {
+			task(i)
+		}
+		return
+	}
+
+	var perItem chan struct{}
+	if limit := limitter.FieldWorkersPerItem(); limit > 0 {
+		perItem = make(chan struct{}, limit)
+	}
+	global := limitter.FieldWorkersLimiter()
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		if perItem != nil {
+			perItem <- struct{}{}
+		}
+
+		if global != nil && !global.TryAcquire(1) {
+			task(i)
+			if perItem != nil {
+				<-perItem
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			if global != nil {
+				defer global.Release(1)
+			}
+			if perItem != nil {
+				defer func() {
+					<-perItem
+				}()
+			}
+
+			task(index)
+		}(i)
+	}
+
+	wg.Wait()
+}
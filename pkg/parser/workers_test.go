@@ -0,0 +1,118 @@
+package parser_test
+
+import (
+	"github.com/PxyUp/fitter/pkg/config"
+	"github.com/PxyUp/fitter/pkg/limitter"
+	"github.com/PxyUp/fitter/pkg/logger"
+	"github.com/PxyUp/fitter/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFieldWorkersNestedModels(t *testing.T) {
+	limitter.ReplaceLimits(&config.Limits{
+		FieldWorkers:        2,
+		FieldWorkersPerItem: 1,
+	})
+	defer limitter.ReplaceLimits(nil)
+
+	nested := &config.Field{
+		BaseField: &config.BaseField{
+			Type: config.Int,
+			Generated: &config.GeneratedFieldConfig{
+				Model: &config.ModelField{
+					Type: config.Array,
+					ConnectorConfig: &config.ConnectorConfig{
+						ResponseType: config.Json,
+						StaticConfig: &config.StaticConnectorConfig{
+							Value: "[{PL}, {PL}]",
+						},
+					},
+					Model: &config.Model{
+						ArrayConfig: &config.ArrayConfig{
+							RootPath: "@this",
+							ItemConfig: &config.ObjectConfig{
+								Field: &config.BaseField{
+									Type: config.Int,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	done := make(chan string, 1)
+	go func() {
+		res, err := parser.NewJson([]byte(`[1, 2, 3, 4, 5, 6, 7, 8]`), logger.Null).Parse(&config.Model{
+			ArrayConfig: &config.ArrayConfig{
+				RootPath: "@this",
+				ItemConfig: &config.ObjectConfig{
+					Fields: map[string]*config.Field{
+						"a": nested,
+						"b": nested,
+					},
+				},
+			},
+		}, nil)
+		require.NoError(t, err)
+		done <- res.ToJson()
+	}()
+
+	select {
+	case res := <-done:
+		assert.JSONEq(t, `[
+			{"a": [1, 1], "b": [1, 1]}, {"a": [2, 2], "b": [2, 2]}, {"a": [3, 3], "b": [3, 3]}, {"a": [4, 4], "b": [4, 4]},
+			{"a": [5, 5], "b": [5, 5]}, {"a": [6, 6], "b": [6, 6]}, {"a": [7, 7], "b": [7, 7]}, {"a": [8, 8], "b": [8, 8]}
+		]`, res)
+	case <-time.After(10 * time.Second):
+		t.Fatal("field resolution deadlocked with bounded workers")
+	}
+}
+
+func TestSequentialFieldsDeterministicOrder(t *testing.T) {
+	limitter.ReplaceLimits(&config.Limits{
+		SequentialFields: true,
+	})
+	defer limitter.ReplaceLimits(nil)
+
+	dir := t.TempDir()
+	storage := func(value string) *config.Field {
+		return &config.Field{
+			BaseField: &config.BaseField{
+				Type: config.String,
+				Generated: &config.GeneratedFieldConfig{
+					FileStorageField: &config.FileStorageField{
+						Content:  value,
+						FileName: "order.log",
+						Path:     dir,
+						Append:   true,
+					},
+				},
+			},
+		}
+	}
+
+	_, err := parser.NewJson([]byte(`[1, 2, 3]`), logger.Null).Parse(&config.Model{
+		ArrayConfig: &config.ArrayConfig{
+			RootPath: "@this",
+			ItemConfig: &config.ObjectConfig{
+				Fields: map[string]*config.Field{
+					"c": storage("c{PL};"),
+					"a": storage("a{PL};"),
+					"b": storage("b{PL};"),
+				},
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(dir, "order.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "a1;b1;c1;a2;b2;c2;a3;b3;c3;", string(content))
+}
@@ -9,11 +9,29 @@ import (
 
 func NewXPath(body []byte, logger logger.Logger) *engineParser[*html.Node] {
 	document, _ := htmlquery.Parse(bytes.NewReader(body))
+	var baseHref string
+	if base := htmlquery.FindOne(document, "//base[@href]"); base != nil {
+		baseHref = htmlquery.SelectAttr(base, "href")
+	}
 
 	return &engineParser[*html.Node]{
-		getText:    htmlquery.InnerText,
+		getText: htmlquery.InnerText,
+		getHTML: func(node *html.Node) string {
+			return htmlquery.OutputHTML(node, false)
+		},
 		parserBody: document,
 		logger:     logger,
+		baseHref:   baseHref,
+		getTable: func(top *html.Node, expr string) *html.Node {
+			if expr == "" {
+				return top
+			}
+			node, err := htmlquery.Query(top, expr)
+			if err != nil {
+				return nil
+			}
+			return node
+		},
 		getAll: func(top *html.Node, expr string) []*html.Node {
 			nodes, err := htmlquery.QueryAll(top, expr)
 			if err != nil {
@@ -3,6 +3,7 @@ package processor
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/PxyUp/fitter/pkg/builder"
 	"github.com/PxyUp/fitter/pkg/config"
 	"github.com/PxyUp/fitter/pkg/logger"
@@ -19,6 +20,9 @@ var (
 
 type Processor interface {
 	Process(ctx context.Context, input builder.Interfacable) (*parser.ParseResult, error)
+	// Stream emits array items one by one as they are built; notifiers are not
+	// involved, the caller consumes the items
+	Stream(ctx context.Context, input builder.Interfacable, emit func(builder.Interfacable) bool) error
 }
 
 type processor struct {
@@ -48,6 +52,10 @@ func (n *nullProcessor) Process(ctx context.Context, input builder.Interfacable)
 	return nil, n.err
 }
 
+func (n *nullProcessor) Stream(ctx context.Context, input builder.Interfacable, emit func(builder.Interfacable) bool) error {
+	return n.err
+}
+
 func New(name string, engine parser.Engine, model *config.Model, notifier notifier.Notifier, notifierCfg *config.NotifierConfig) *processor {
 	return &processor{
 		name:        name,
@@ -103,11 +111,54 @@ func (p *processor) Process(ctx context.Context, input builder.Interfacable) (*p
 	return result, nil
 }
 
+func (p *processor) Stream(ctx context.Context, input builder.Interfacable, emit func(builder.Interfacable) bool) error {
+	err := p.engine.Stream(ctx, p.model, nil, nil, input, emit)
+	if err != nil {
+		p.logger.Errorw("parser return error streaming data", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+// prepare validates the item at load time and pre-compiles its expressions
+func prepare(item *config.Item, refMap config.RefMap) error {
+	if err := parser.PrepareModel(item.Model, "item.model"); err != nil {
+		return err
+	}
+
+	if item.NotifierConfig != nil {
+		if err := utils.ValidateExpression(item.NotifierConfig.Expression); err != nil {
+			return fmt.Errorf("item.notifier_config.expression: invalid expression %q: %w", item.NotifierConfig.Expression, err)
+		}
+		if item.NotifierConfig.SQL != nil {
+			if err := notifier.ValidateSQL(item.NotifierConfig.SQL); err != nil {
+				return fmt.Errorf("item.notifier_config.sql: %w", err)
+			}
+		}
+	}
+
+	for name, ref := range refMap {
+		if ref == nil || ref.ModelField == nil {
+			continue
+		}
+		if err := parser.PrepareModel(ref.Model, fmt.Sprintf("references.%s.model", name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func CreateProcessor(item *config.Item, refMap config.RefMap, logger logger.Logger) Processor {
 	if item.Name == "" {
 		return Null(errMissingName, nil)
 	}
 
+	if err := prepare(item, refMap); err != nil {
+		logger.Errorw("invalid configuration", "name", item.Name, "error", err.Error())
+		return Null(err)
+	}
+
 	references.SetReference(refMap, func(refName string, model *config.ModelField) (builder.Jsonable, error) {
 		return parser.NewEngine(model.ConnectorConfig, logger.With("reference_name", refName)).Get(context.Background(), model.Model, nil, nil, nil)
 	})
@@ -134,6 +185,10 @@ func CreateProcessor(item *config.Item, refMap config.RefMap, logger logger.Logg
 		if item.NotifierConfig.File != nil {
 			notifierInstance = notifier.NewFile(item.Name, item.NotifierConfig.File).WithLogger(logger.With("notifier", "file"))
 		}
+
+		if item.NotifierConfig.SQL != nil {
+			notifierInstance = notifier.NewSQL(item.Name, item.NotifierConfig.SQL).WithLogger(logger.With("notifier", "sql"))
+		}
 	}
 
 	logger = logger.With("name", item.Name)
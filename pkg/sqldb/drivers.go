@@ -0,0 +1,9 @@
+//go:build !js
+
+package sqldb
+
+import (
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
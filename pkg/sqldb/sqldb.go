@@ -0,0 +1,166 @@
+package sqldb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PxyUp/fitter/pkg/builder"
+	"github.com/PxyUp/fitter/pkg/config"
+)
+
+var (
+	errUnsupportedDriver = errors.New("unsupported sql driver")
+	errEmptyDSN          = errors.New("empty sql dsn")
+
+	// pools are shared per driver and dsn, like http transports
+	dbs   = make(map[string]*sql.DB)
+	mutex sync.Mutex
+)
+
+// Open returns the shared connection pool for the driver and dsn
+func Open(driver config.SQLDriver, dsn string) (*sql.DB, error) {
+	switch driver {
+	case config.SQLite, config.Postgres, config.MySQL:
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnsupportedDriver, driver)
+	}
+
+	if dsn == "" {
+		return nil, errEmptyDSN
+	}
+
+	key := string(driver) + "|" + dsn
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if db, ok := dbs[key]; ok {
+		return db, nil
+	}
+
+	db, err := sql.Open(string(driver), dsn)
+	if err != nil {
+		return nil, err
+	}
+	if driver == config.SQLite {
+		// sqlite allows only one writer, serialize instead of failing with "database is locked"
+		db.SetMaxOpenConns(1)
+	}
+
+	dbs[key] = db
+	return db, nil
+}
+
+// Arg converts a parsed value into a bind parameter: objects and arrays are
+// sent as json strings, numbers without fraction as int64
+func Arg(value builder.Interfacable) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	switch v := value.ToInterface().(type) {
+	case map[string]interface{}, []interface{}:
+		return value.ToJson()
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// EachRow passes every row as object (column name - json value) to fn until
+// it returns false or an error
+func EachRow(rows *sql.Rows, fn func(row map[string]interface{}) (bool, error)) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		err = rows.Scan(pointers...)
+		if err != nil {
+			return err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			row[column] = jsonValue(values[i])
+		}
+		next, errRow := fn(row)
+		if errRow != nil {
+			return errRow
+		}
+		if !next {
+			return nil
+		}
+	}
+
+	return rows.Err()
+}
+
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
+
+// Placeholder returns the bind placeholder of the parameter at position (1-based)
+func Placeholder(driver config.SQLDriver, position int) string {
+	if driver == config.Postgres {
+		return fmt.Sprintf("$%d", position)
+	}
+	return "?"
+}
+
+// QuoteIdentifier quotes a table or column name for the driver
+func QuoteIdentifier(driver config.SQLDriver, name string) string {
+	if driver == config.MySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// ColumnType maps a field type to the column type of the driver; key
+// columns of mysql need a bounded length
+func ColumnType(driver config.SQLDriver, fieldType config.FieldType, key bool) string {
+	switch fieldType {
+	case config.Int, config.Int64:
+		if driver == config.SQLite {
+			return "INTEGER"
+		}
+		return "BIGINT"
+	case config.Float, config.Float64:
+		switch driver {
+		case config.SQLite:
+			return "REAL"
+		case config.MySQL:
+			return "DOUBLE"
+		}
+		return "DOUBLE PRECISION"
+	case config.Bool:
+		return "BOOLEAN"
+	}
+
+	if driver == config.MySQL && key {
+		return "VARCHAR(255)"
+	}
+	return "TEXT"
+}
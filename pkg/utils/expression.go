@@ -1,10 +1,14 @@
 package utils
 
 import (
+	"container/list"
 	"encoding/json"
 	"github.com/PxyUp/fitter/pkg/builder"
 	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/types"
+	"github.com/expr-lang/expr/vm"
 	"strings"
+	"sync"
 )
 
 const (
@@ -15,6 +19,10 @@ const (
 	fitterResultRaw               = "fResRaw"
 	fitterNewLinePlaceholderKey   = "FNewLine"
 	fitterNewLinePlaceholderValue = "$__FLINE__$"
+
+	// maxCachedPrograms bounds the program cache: expressions with placeholders
+	// are formatted before compilation, so every distinct value adds a key
+	maxCachedPrograms = 4096
 )
 
 var (
@@ -26,8 +34,74 @@ var (
 			return builder.NullValue == value
 		},
 	}
+
+	// compileEnv declares every variable of the runtime env: the helpers and
+	// fIndex/fResJson/fResRaw keep their types, fRes and fSrc depend on the
+	// parsed data and stay dynamic
+	compileEnv = types.Map{
+		fitterNewLinePlaceholderKey: types.String,
+		"FNull":                     types.TypeOf(builder.NullValue),
+		"FNil":                      types.Nil,
+		"isNull":                    types.TypeOf(defEnv["isNull"]),
+		fitterResultRaw:             types.TypeOf(json.RawMessage(nil)),
+		fitterResultRef:             types.Any,
+		fitterResultJsonRef:         types.String,
+		fitterSourceRef:             types.Any,
+		fitterIndexRef:              types.Uint32,
+	}
+
+	programs = newProgramCache(maxCachedPrograms)
 )
 
+// programCache is the lru of compiled programs
+type programCache struct {
+	mu      sync.Mutex
+	limit   int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type cachedProgram struct {
+	key     string
+	program *vm.Program
+}
+
+func newProgramCache(limit int) *programCache {
+	return &programCache{
+		limit:   limit,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *programCache) get(key string) (*vm.Program, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*cachedProgram).program, true
+}
+
+func (c *programCache) add(key string, program *vm.Program) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cachedProgram{key: key, program: program})
+	if c.order.Len() > c.limit {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedProgram).key)
+	}
+}
+
 func extendEnv(env map[string]interface{}, result builder.Interfacable, index *uint32) map[string]interface{} {
 	kv := make(map[string]interface{})
 
@@ -47,20 +121,36 @@ func extendEnv(env map[string]interface{}, result builder.Interfacable, index *u
 	return kv
 }
 
-// ValidateExpression compiles the expression without running it, catching
-// syntax errors at validation time. Variables stay dynamically typed (their
-// real types depend on the parsed data), and expressions containing
-// placeholder syntax are skipped: they are formatted at runtime before
-// compilation, so they cannot be checked statically
+// ValidateExpression compiles the expression at config load time and keeps
+// the program in the cache, so the runtime evaluation doesn't compile it
+// again. Expressions containing placeholder syntax are skipped: they are
+// formatted at runtime and compiled on the first use of every formatted value
 func ValidateExpression(expression string) error {
 	if expression == "" || strings.Contains(expression, "{") {
 		return nil
 	}
 
-	_, err := expr.Compile(expression, expr.AllowUndefinedVariables())
+	_, err := compileExpression(expression)
 	return err
 }
 
+// compileExpression returns the cached program for the already formatted
+// expression. Programs are compiled against compileEnv, so one program serves
+// every runtime env and the expression alone is the key
+func compileExpression(expression string) (*vm.Program, error) {
+	if program, ok := programs.get(expression); ok {
+		return program, nil
+	}
+
+	program, err := expr.Compile(expression, expr.Env(compileEnv))
+	if err != nil {
+		return nil, err
+	}
+
+	programs.add(expression, program)
+	return program, nil
+}
+
 // ProcessCondition reports whether the expression resolved to boolean true
 func ProcessCondition(expression string, result builder.Interfacable, index *uint32, input builder.Interfacable) (bool, error) {
 	return ProcessConditionWithSource(expression, result, nil, index, input)
@@ -87,7 +177,7 @@ func ProcessExpression(expression string, result builder.Interfacable, index *ui
 }
 
 func processExpression(env map[string]interface{}, expression string, result builder.Interfacable, index *uint32, input builder.Interfacable) (builder.Interfacable, error) {
-	program, err := expr.Compile(Format(expression, result, index, input), expr.Env(env))
+	program, err := compileExpression(Format(expression, result, index, input))
 	if err != nil {
 		return nil, err
 	}
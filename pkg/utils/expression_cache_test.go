@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/expr-lang/expr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgramCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newProgramCache(2)
+	for i := 0; i < 3; i++ {
+		program, err := expr.Compile(fmt.Sprintf("%d", i))
+		require.NoError(t, err)
+		cache.add(fmt.Sprintf("%d", i), program)
+		if i == 1 {
+			// touch "0" so "1" becomes the oldest
+			_, ok := cache.get("0")
+			assert.True(t, ok)
+		}
+	}
+
+	_, ok := cache.get("1")
+	assert.False(t, ok)
+	_, ok = cache.get("0")
+	assert.True(t, ok)
+	_, ok = cache.get("2")
+	assert.True(t, ok)
+	assert.Equal(t, 2, cache.order.Len())
+}
+
+func TestValidateExpressionWarmsCache(t *testing.T) {
+	expression := "fRes.price > 5 && fIndex != 7"
+	require.NoError(t, ValidateExpression(expression))
+
+	program, ok := programs.get(expression)
+	require.True(t, ok)
+
+	compiled, err := compileExpression(expression)
+	require.NoError(t, err)
+	assert.Same(t, program, compiled)
+
+	require.NoError(t, ValidateExpression("fRes > {{{limit}}}"))
+	_, ok = programs.get("fRes > {{{limit}}}")
+	assert.False(t, ok)
+}
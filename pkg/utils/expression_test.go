@@ -3,6 +3,7 @@ package utils_test
 import (
 	"github.com/PxyUp/fitter/pkg/builder"
 	"github.com/PxyUp/fitter/pkg/utils"
+	"github.com/expr-lang/expr"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -90,3 +91,57 @@ func TestProcessConditionWithSource(t *testing.T) {
 	assert.NoError(t, err)
 	assert.False(t, pass)
 }
+
+func TestProcessExpressionUnknownVariable(t *testing.T) {
+	_, err := utils.ProcessExpression("fREs + 1", builder.Number(1), nil, nil)
+	assert.ErrorContains(t, err, "unknown name fREs")
+
+	// one program serves values of any type
+	out, err := utils.ProcessExpression("fRes + fRes", builder.Number(1), nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", out.ToJson())
+	out, err = utils.ProcessExpression("fRes + fRes", builder.String("a"), nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `"aa"`, out.ToJson())
+}
+
+func TestValidateExpression(t *testing.T) {
+	assert.NoError(t, utils.ValidateExpression(""))
+	assert.NoError(t, utils.ValidateExpression("fRes > {{{limit}}}"))
+	assert.NoError(t, utils.ValidateExpression("fRes.price > 5 && fIndex != 1"))
+	assert.Error(t, utils.ValidateExpression("fRes >>> nonsense"))
+}
+
+func BenchmarkProcessExpression(b *testing.B) {
+	index := uint32(3)
+	result := builder.Object(map[string]builder.Interfacable{
+		"price":    builder.Number(10),
+		"in_stock": builder.Bool(true),
+	})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = utils.ProcessCondition("fRes.in_stock && fRes.price > 5 && fIndex % 2 == 1", result, &index, nil)
+	}
+}
+
+// BenchmarkCompileExpressionPerCall is the cost every evaluation paid before
+// compiled programs were cached
+func BenchmarkCompileExpressionPerCall(b *testing.B) {
+	env := map[string]interface{}{
+		"fRes": map[string]interface{}{
+			"price":    float64(10),
+			"in_stock": true,
+		},
+		"fIndex": uint32(3),
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		program, err := expr.Compile("fRes.in_stock && fRes.price > 5 && fIndex % 2 == 1", expr.Env(env))
+		if err != nil {
+			b.Fatal(err)
+		}
+		_, _ = expr.Run(program, env)
+	}
+}